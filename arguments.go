@@ -0,0 +1,423 @@
+package goldengine
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ArgumentsError : Everything wrong with the Arguments of a prefab
+type ArgumentsError struct {
+	Problems []string
+}
+
+func (err *ArgumentsError) Error() string {
+	return strings.Join(err.Problems, "; ")
+}
+
+//argField : Field of a config struct and the options of its ge tag
+type argField struct {
+	index    int
+	name     string
+	def      *string
+	min, max *float64
+	required bool
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+//argFields : Fields of a config struct that arguments are decoded into
+func argFields(t reflect.Type) ([]argField, error) {
+	var fields []argField
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if structField.PkgPath != "" {
+			continue
+		}
+		field := argField{
+			index: i,
+			name:  structField.Name,
+		}
+		tag, ok := structField.Tag.Lookup("ge")
+		if tag == "-" {
+			continue
+		}
+		if ok {
+			options := strings.Split(tag, ",")
+			if options[0] != "" {
+				field.name = options[0]
+			}
+			for _, option := range options[1:] {
+				key, value, _ := strings.Cut(option, "=")
+				switch key {
+				case "default":
+					v := value
+					field.def = &v
+				case "min", "max":
+					bound, err := strconv.ParseFloat(value, 64)
+					if err != nil {
+						return nil, fmt.Errorf("%s.%s: bad %s %q", t.Name(), structField.Name, key, value)
+					}
+					if key == "min" {
+						field.min = &bound
+					} else {
+						field.max = &bound
+					}
+				case "required":
+					field.required = true
+				default:
+					return nil, fmt.Errorf("%s.%s: unknown ge tag option %q", t.Name(), structField.Name, key)
+				}
+			}
+		}
+		if field.def != nil {
+			if err := decodeDefault(*field.def, reflect.New(structField.Type).Elem()); err != nil {
+				return nil, fmt.Errorf("%s.%s: %v", t.Name(), structField.Name, err)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+//DecodeArguments : Fills the struct out points to from the Arguments of a prefab
+/*
+	type PaddleConfig struct {
+		Speed float32       `ge:"speed,default=400,min=0"`
+		Key   string        `ge:"key,required"`
+		Delay time.Duration `ge:"delay,default=250ms"`
+	}
+
+Keys match the tag name, or the field name without one, ignoring case.
+Fields tagged "-" and unexported fields are skipped. Nested structs like
+Vector and sf.Color are decoded from objects and slices from arrays. A
+time.Duration is either a string like "1.5s" or a number of seconds.
+Unknown keys, wrong types, values outside min and max and missing
+required fields are all reported in one ArgumentsError
+*/
+func DecodeArguments(args map[string]interface{}, out interface{}) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("DecodeArguments needs a pointer to a struct, got %T", out)
+	}
+	var problems []string
+	if err := decodeStruct("", args, v.Elem(), &problems); err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return &ArgumentsError{Problems: problems}
+	}
+	return nil
+}
+
+func decodeStruct(path string, args map[string]interface{}, v reflect.Value, problems *[]string) error {
+	fields, err := argFields(v.Type())
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]string, len(args))
+	for key := range args {
+		keys[strings.ToLower(key)] = key
+	}
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		lower := strings.ToLower(field.name)
+		known[lower] = true
+		target := v.Field(field.index)
+		key, ok := keys[lower]
+		if !ok {
+			switch {
+			case field.def != nil:
+				decodeDefault(*field.def, target)
+			case field.required:
+				*problems = append(*problems, fmt.Sprintf("missing required argument %s", path+field.name))
+			}
+			continue
+		}
+		if err := decodeValue(path+key, args[key], target, problems); err != nil {
+			return err
+		}
+		checkBounds(path+key, field, target, problems)
+	}
+	var unknown []string
+	for key := range args {
+		if !known[strings.ToLower(key)] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		*problems = append(*problems, fmt.Sprintf("unknown argument %s", path+key))
+	}
+	return nil
+}
+
+//decodeValue : Sets v from a value the JSON parser made
+func decodeValue(path string, raw interface{}, v reflect.Value, problems *[]string) error {
+	mismatch := func() {
+		*problems = append(*problems, fmt.Sprintf("argument %s should be %s, got %s", path, describeType(v.Type()), describeJSON(raw)))
+	}
+	if v.Type() == durationType {
+		switch value := raw.(type) {
+		case string:
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				*problems = append(*problems, fmt.Sprintf("argument %s: %v", path, err))
+				return nil
+			}
+			v.SetInt(int64(d))
+		default:
+			seconds, ok := argNumber(raw)
+			if !ok {
+				mismatch()
+				return nil
+			}
+			v.SetInt(int64(seconds * float64(time.Second)))
+		}
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		value, ok := raw.(bool)
+		if !ok {
+			mismatch()
+			return nil
+		}
+		v.SetBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, ok := argNumber(raw)
+		if !ok || value != math.Trunc(value) || v.OverflowInt(int64(value)) {
+			mismatch()
+			return nil
+		}
+		v.SetInt(int64(value))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, ok := argNumber(raw)
+		if !ok || value < 0 || value != math.Trunc(value) || v.OverflowUint(uint64(value)) {
+			mismatch()
+			return nil
+		}
+		v.SetUint(uint64(value))
+	case reflect.Float32, reflect.Float64:
+		value, ok := argNumber(raw)
+		if !ok {
+			mismatch()
+			return nil
+		}
+		v.SetFloat(value)
+	case reflect.String:
+		value, ok := raw.(string)
+		if !ok {
+			mismatch()
+			return nil
+		}
+		v.SetString(value)
+	case reflect.Slice:
+		list, ok := raw.([]interface{})
+		if !ok {
+			mismatch()
+			return nil
+		}
+		slice := reflect.MakeSlice(v.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := decodeValue(fmt.Sprintf("%s[%d]", path, i), item, slice.Index(i), problems); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Map:
+		object, ok := raw.(map[string]interface{})
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			mismatch()
+			return nil
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(object))
+		for key, item := range object {
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeValue(path+"."+key, item, value, problems); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), value)
+		}
+		v.Set(m)
+	case reflect.Struct:
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			mismatch()
+			return nil
+		}
+		return decodeStruct(path+".", object, v, problems)
+	case reflect.Ptr:
+		value := reflect.New(v.Type().Elem())
+		if err := decodeValue(path, raw, value.Elem(), problems); err != nil {
+			return err
+		}
+		v.Set(value)
+	case reflect.Interface:
+		if raw != nil && !reflect.TypeOf(raw).AssignableTo(v.Type()) {
+			mismatch()
+			return nil
+		}
+		if raw != nil {
+			v.Set(reflect.ValueOf(raw))
+		}
+	default:
+		return fmt.Errorf("argument %s: can't decode into %s", path, v.Type())
+	}
+	return nil
+}
+
+//argNumber : Number from the JSON parser, or any Go number when arguments
+//come from code like InstantiateOptions
+func argNumber(raw interface{}) (float64, bool) {
+	v := reflect.ValueOf(raw)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+//decodeDefault : Sets v from the default option of a ge tag
+func decodeDefault(def string, v reflect.Value) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(def)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(def)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(def, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(def, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(def, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		v.SetString(def)
+	default:
+		return fmt.Errorf("%s can't have a default", v.Type())
+	}
+	return nil
+}
+
+//checkBounds : Reports numbers outside the min and max of their field
+func checkBounds(path string, field argField, v reflect.Value, problems *[]string) {
+	if field.min == nil && field.max == nil {
+		return
+	}
+	var value float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		value = v.Float()
+	default:
+		return
+	}
+	if field.min != nil && value < *field.min {
+		*problems = append(*problems, fmt.Sprintf("argument %s is %v, less than the minimum %v", path, value, *field.min))
+	}
+	if field.max != nil && value > *field.max {
+		*problems = append(*problems, fmt.Sprintf("argument %s is %v, more than the maximum %v", path, value, *field.max))
+	}
+}
+
+func describeType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number that fits in " + t.String()
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return t.String()
+}
+
+func describeJSON(raw interface{}) string {
+	switch value := raw.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(value)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case string:
+		return strconv.Quote(value)
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", raw)
+}
+
+//RegisterConfigured : Registers a component built from a config struct that
+//prefab Arguments are decoded into with DecodeArguments. Bad tags on the
+//config struct are reported here instead of when a prefab is loaded
+func RegisterConfigured[C any](name string, build func(config C) Component, requires ...string) error {
+	t := reflect.TypeOf((*C)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("Config of component %s must be a struct, got %s", name, t)
+	}
+	if _, err := argFields(t); err != nil {
+		return err
+	}
+	builder := func(args map[string]interface{}) (Component, error) {
+		var config C
+		if err := DecodeArguments(args, &config); err != nil {
+			return nil, err
+		}
+		return build(config), nil
+	}
+	generator := func(args map[string]interface{}) Component {
+		comp, err := builder(args)
+		if err != nil {
+			panic(fmt.Errorf("Component %s: %v", name, err))
+		}
+		return comp
+	}
+	if err := ComponentRegister.RegisterRequiring(name, generator, requires...); err != nil {
+		return err
+	}
+	ComponentRegister.builders[name] = builder
+	return nil
+}
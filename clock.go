@@ -0,0 +1,81 @@
+package goldengine
+
+//GamePausedMSG : Game was paused. Sends pointer to game
+const GamePausedMSG = MessageType("GamePaused")
+
+//GameResumedMSG : Game was resumed. Sends pointer to game
+const GameResumedMSG = MessageType("GameResumed")
+
+//GameTimeScaleChangedMSG : Game time scale changed. Sends pointer to game
+const GameTimeScaleChangedMSG = MessageType("GameTimeScaleChanged")
+
+//Pause : Stops fixed updates until Resume. Drawing and input continue
+func (g *Game) Pause() {
+	g.clockLock.Lock()
+	if g.paused {
+		g.clockLock.Unlock()
+		return
+	}
+	g.paused = true
+	g.clockLock.Unlock()
+	g.PostOffice.Broadcast(Message{
+		Message: GamePausedMSG,
+		Content: g,
+	})
+}
+
+//Resume : Continues fixed updates after Pause
+func (g *Game) Resume() {
+	g.clockLock.Lock()
+	if !g.paused {
+		g.clockLock.Unlock()
+		return
+	}
+	g.paused = false
+	g.pendingSteps = 0
+	g.clockLock.Unlock()
+	g.PostOffice.Broadcast(Message{
+		Message: GameResumedMSG,
+		Content: g,
+	})
+}
+
+//IsPaused : Whether the game is paused
+func (g *Game) IsPaused() bool {
+	g.clockLock.Lock()
+	defer g.clockLock.Unlock()
+	return g.paused
+}
+
+//StepFrame : While paused, runs exactly one fixed update on the next Advance.
+//Does nothing while running
+func (g *Game) StepFrame() {
+	g.clockLock.Lock()
+	defer g.clockLock.Unlock()
+	if g.paused {
+		g.pendingSteps++
+	}
+}
+
+//TimeScale : How fast simulated time passes compared to real time
+func (g *Game) TimeScale() float64 {
+	g.clockLock.Lock()
+	defer g.clockLock.Unlock()
+	return g.timeScale
+}
+
+//SetTimeScale : Scales how much simulated time passes every real second.
+//0.5 is slow motion and 2 is fast forward. Fixed updates keep the same
+//length so the simulation stays deterministic
+func (g *Game) SetTimeScale(scale float64) {
+	if scale < 0 {
+		scale = 0
+	}
+	g.clockLock.Lock()
+	g.timeScale = scale
+	g.clockLock.Unlock()
+	g.PostOffice.Broadcast(Message{
+		Message: GameTimeScaleChangedMSG,
+		Content: g,
+	})
+}
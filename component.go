@@ -2,6 +2,7 @@ package goldengine
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -48,6 +49,111 @@ func (b *BaseComponent) GetEntity() *Entity {
 	return b.entity
 }
 
+//Interpolator : Optional Component method. Called before drawing with how far
+//the game is between the last fixed update and the next one, from 0 to 1
+type Interpolator interface {
+	Interpolate(alpha float32)
+}
+
+//GetComponent : First component of the entity that is a T. T can be a
+//component type like *PaddleComponent or an interface like Interpolator
+func GetComponent[T any](e *Entity) (T, bool) {
+	for _, c := range e.components {
+		if t, ok := c.(T); ok {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+//GetComponents : Every component of the entity that is a T, in the order they were added
+func GetComponents[T any](e *Entity) []T {
+	var list []T
+	for _, c := range e.components {
+		if t, ok := c.(T); ok {
+			list = append(list, t)
+		}
+	}
+	return list
+}
+
+//GetComponentInChildren : GetComponent on the entity, then on its children
+//depth first in the order they were created
+func GetComponentInChildren[T any](e *Entity) (T, bool) {
+	if t, ok := GetComponent[T](e); ok {
+		return t, true
+	}
+	for _, child := range e.sortedChildren() {
+		if t, ok := GetComponentInChildren[T](child); ok {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+//HasComponent : Entity has a component that is a T
+func HasComponent[T any](e *Entity) bool {
+	_, ok := GetComponent[T](e)
+	return ok
+}
+
+//Requirer : Optional Component method. Names of registered components, or
+//RequireCollider and RequireTransformer, the component needs on its entity
+type Requirer interface {
+	Requires() []string
+}
+
+const (
+	//RequireCollider : Requirement met by entities with a Collider
+	RequireCollider = "@Collider"
+	//RequireTransformer : Requirement met by entities with a Transformer
+	RequireTransformer = "@Transformer"
+)
+
+//componentRequirements : What the register and the component itself say it requires
+func componentRequirements(name string, comp Component) []string {
+	var list []string
+	if name != "" {
+		list = append(list, ComponentRegister.Requirements(name)...)
+	}
+	if r, ok := comp.(Requirer); ok {
+		list = append(list, r.Requires()...)
+	}
+	return list
+}
+
+//orderPrefabComponents : Positions of a prefab's built components with the
+//ones others require first, through ComponentRegister or Requirer, otherwise in
+//the order they were written. Requirements then find the prefab's own component
+//with its arguments instead of adding one with default arguments
+func orderPrefabComponents(list []ComponentPrefab, comps []Component) []int {
+	index := make(map[string]int)
+	for i := len(list) - 1; i >= 0; i-- {
+		index[list[i].Name] = i
+	}
+	visited := make([]bool, len(list))
+	ordered := make([]int, 0, len(list))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, required := range componentRequirements(list[i].Name, comps[i]) {
+			if j, ok := index[required]; ok {
+				visit(j)
+			}
+		}
+		ordered = append(ordered, i)
+	}
+	for i := range list {
+		visit(i)
+	}
+	return ordered
+}
+
 // ComponentPrefab : Generates a Component with the Given name from the Arguments
 type ComponentPrefab struct {
 	Name      string
@@ -69,11 +175,20 @@ type ComponentGenerator func(args map[string]interface{}) Component
 // ComponentRegister : Registers a Generator With a ComponentName
 type componentRegister struct {
 	register map[string]ComponentGenerator
+	requires map[string][]string
+	//Generators that report bad arguments instead of panicking
+	builders   map[string]func(args map[string]interface{}) (Component, error)
+	priorities map[string]int
+	//Bumped when priorities change so scenes sort their components again
+	version uint64
 }
 
 // ComponentRegister : map of generators for a componentName
 var ComponentRegister = componentRegister{
-	register: make(map[string]ComponentGenerator),
+	register:   make(map[string]ComponentGenerator),
+	requires:   make(map[string][]string),
+	builders:   make(map[string]func(args map[string]interface{}) (Component, error)),
+	priorities: make(map[string]int),
 }
 
 // ErrComponentAlreadyRegistered : Cannot register a ComponentGenerator because the name is already registered
@@ -89,8 +204,60 @@ func (c *componentRegister) Register(name string, generator ComponentGenerator)
 	return nil
 }
 
+// RegisterRequiring : Register a ComponentGenerator whose components need others on their entity
+func (c *componentRegister) RegisterRequiring(name string, generator ComponentGenerator, requires ...string) error {
+	if err := c.Register(name, generator); err != nil {
+		return err
+	}
+	c.SetRequirements(name, requires...)
+	return nil
+}
+
+// SetRequirements : Names of registered components, or RequireCollider and
+// RequireTransformer, that components with the name need on their entity.
+// Missing components are added before them and start and wake up first
+func (c *componentRegister) SetRequirements(name string, requires ...string) {
+	c.requires[name] = append([]string(nil), requires...)
+}
+
+// Requirements : What components with the name need on their entity
+func (c *componentRegister) Requirements(name string) []string {
+	return c.requires[name]
+}
+
+// SetPriority : Components with a lower priority update before ones with a
+// higher priority in every scene, like input before movement. Components
+// with the same priority update in tree order. The default is 0
+func (c *componentRegister) SetPriority(name string, priority int) {
+	c.priorities[name] = priority
+	c.version++
+}
+
+// Priority : Update priority of components with the name
+func (c *componentRegister) Priority(name string) int {
+	return c.priorities[name]
+}
+
+// UnRegister : Forgets a component, its requirements and its priority
 func (c *componentRegister) UnRegister(name string) {
 	delete(c.register, name)
+	delete(c.requires, name)
+	delete(c.builders, name)
+	delete(c.priorities, name)
+	c.version++
+}
+
+// build : Creates a component from arguments, with an error for bad arguments
+// of components registered with RegisterConfigured
+func (c *componentRegister) build(name string, args map[string]interface{}) (Component, error) {
+	if builder, ok := c.builders[name]; ok {
+		return builder(args)
+	}
+	generator, ok := c.register[name]
+	if !ok {
+		return nil, fmt.Errorf("No component with the name %s", name)
+	}
+	return generator(args), nil
 }
 
 func (c *componentRegister) Get(name string) (ComponentGenerator, bool) {
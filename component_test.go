@@ -0,0 +1,231 @@
+package goldengine
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testEngineConfig struct {
+	Power int `ge:"power,default=1"`
+}
+
+type testEngine struct {
+	Power int
+	BaseComponent
+}
+
+type testCar struct {
+	BaseComponent
+}
+
+//Requires : Cars need an engine
+func (c *testCar) Requires() []string {
+	return []string{"testEngine"}
+}
+
+func TestPrefabOrdersRequirerDependencies(t *testing.T) {
+	err := RegisterConfigured("testEngine", func(config testEngineConfig) Component {
+		return &testEngine{Power: config.Power}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ComponentRegister.UnRegister("testEngine")
+	err = ComponentRegister.Register("testCar", func(map[string]interface{}) Component {
+		return &testCar{}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ComponentRegister.UnRegister("testCar")
+
+	e, err := EntityFromEntityPrefab(EntityPrefab{
+		Name: "car",
+		Components: []ComponentPrefab{
+			{Name: "testCar"},
+			{Name: "testEngine", Arguments: map[string]interface{}{"power": 300}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var engines []*testEngine
+	for _, c := range e.Components() {
+		if engine, ok := c.(*testEngine); ok {
+			engines = append(engines, engine)
+		}
+	}
+	if len(engines) != 1 {
+		t.Fatalf("entity has %d engines, want 1", len(engines))
+	}
+	if engines[0].Power != 300 {
+		t.Fatalf("engine power %d, want the prefab's 300", engines[0].Power)
+	}
+	if _, ok := e.Components()[0].(*testEngine); !ok {
+		t.Fatalf("components %v, want the engine before the car requiring it", e.Components())
+	}
+}
+
+func TestPrefabArgumentErrors(t *testing.T) {
+	err := RegisterConfigured("testEngine", func(config testEngineConfig) Component {
+		return &testEngine{Power: config.Power}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ComponentRegister.UnRegister("testEngine")
+	_, err = EntityFromEntityPrefab(EntityPrefab{
+		Name: "car",
+		Components: []ComponentPrefab{
+			{Name: "testEngine", Arguments: map[string]interface{}{"power": "fast"}},
+		},
+	})
+	if err == nil {
+		t.Fatal("bad arguments built an entity")
+	}
+}
+
+func TestUnRegisterForgetsPriority(t *testing.T) {
+	generator := func(map[string]interface{}) Component {
+		return &testCar{}
+	}
+	if err := ComponentRegister.Register("testCar", generator); err != nil {
+		t.Fatal(err)
+	}
+	ComponentRegister.SetPriority("testCar", 5)
+	version := ComponentRegister.version
+	ComponentRegister.UnRegister("testCar")
+	if ComponentRegister.version == version {
+		t.Error("UnRegister didn't change the register version, update orders aren't rebuilt")
+	}
+	if err := ComponentRegister.Register("testCar", generator); err != nil {
+		t.Fatal(err)
+	}
+	defer ComponentRegister.UnRegister("testCar")
+	if p := ComponentRegister.Priority("testCar"); p != 0 {
+		t.Errorf("registered again with priority %d, want 0", p)
+	}
+}
+
+//testCargo : Keeps its arguments and scribbles on them like a careless component
+type testCargo struct {
+	BaseComponent
+	args map[string]interface{}
+}
+
+func registerTestCargo(t *testing.T) {
+	t.Helper()
+	err := ComponentRegister.Register("testCargo", func(args map[string]interface{}) Component {
+		if items, ok := args["items"].([]interface{}); ok && len(items) > 0 {
+			items[0] = "stolen"
+		}
+		if load, ok := args["load"].(map[string]interface{}); ok {
+			load["kind"] = "lead"
+		}
+		return &testCargo{args: args}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEntityPrefabCopyIsDeep(t *testing.T) {
+	prefab := EntityPrefab{
+		Name: "crate",
+		Components: []ComponentPrefab{{
+			Name: "testCargo",
+			Arguments: map[string]interface{}{
+				"items": []interface{}{"rope", map[string]interface{}{"kind": "lamp"}},
+				"load":  map[string]interface{}{"kind": "gold", "weights": []interface{}{1.0, 2.0}},
+			},
+		}},
+		Tags: []string{"crate"},
+	}
+	prefab.Collider.Arguments = map[string]interface{}{"size": map[string]interface{}{"x": 1.0}}
+	copied := prefab.Copy()
+	args := copied.Components[0].Arguments
+	args["items"].([]interface{})[0] = "chain"
+	args["items"].([]interface{})[1].(map[string]interface{})["kind"] = "torch"
+	args["load"].(map[string]interface{})["weights"].([]interface{})[0] = 9.0
+	args["new"] = true
+	copied.Collider.Arguments["size"].(map[string]interface{})["x"] = 5.0
+	copied.Components[0].Name = "other"
+	copied.Tags[0] = "barrel"
+
+	original := prefab.Components[0].Arguments
+	if original["items"].([]interface{})[0] != "rope" {
+		t.Error("copy shares the items slice")
+	}
+	if original["items"].([]interface{})[1].(map[string]interface{})["kind"] != "lamp" {
+		t.Error("copy shares a map nested in a slice")
+	}
+	if original["load"].(map[string]interface{})["weights"].([]interface{})[0] != 1.0 {
+		t.Error("copy shares a slice nested in a map")
+	}
+	if _, ok := original["new"]; ok {
+		t.Error("copy shares the arguments map")
+	}
+	if prefab.Collider.Arguments["size"].(map[string]interface{})["x"] != 1.0 {
+		t.Error("copy shares the collider arguments")
+	}
+	if prefab.Components[0].Name != "testCargo" || prefab.Tags[0] != "crate" {
+		t.Error("copy shares the components or tags")
+	}
+}
+
+func TestInstantiateOverridesDontLeak(t *testing.T) {
+	registerTestCargo(t)
+	defer ComponentRegister.UnRegister("testCargo")
+	game := newTestGame(t, GameConfig{}, nil, map[string]string{
+		"prefabs/crate.json": `{"Name":"crate","Tags":["crate"],"Components":[{"Name":"testCargo",
+			"Arguments":{"items":["rope","lamp"],"load":{"kind":"gold","weight":2}}}]}`,
+		"scenes/main.scene": `{"Name":"main"}`,
+	})
+	game.ChangeScene("main")
+	game.Start()
+	defer game.Stop()
+	scene := game.GetCurrentScene()
+	before, ok := PrefabRegister.Get("crate")
+	if !ok {
+		t.Fatal("crate prefab isn't registered")
+	}
+
+	overrides := map[string]interface{}{
+		"load": map[string]interface{}{"kind": "silver", "weight": 5.0},
+	}
+	first, err := scene.Instantiate("crate", InstantiateOptions{
+		ComponentArguments: map[string]map[string]interface{}{"testCargo": overrides},
+		Tags:               []string{"heavy"},
+		Layer:              "cargo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cargo := first.Components()[0].(*testCargo)
+	if cargo.args["load"].(map[string]interface{})["weight"] != 5.0 {
+		t.Errorf("override didn't reach the component: %v", cargo.args)
+	}
+	if overrides["load"].(map[string]interface{})["kind"] != "silver" {
+		t.Error("the component changed the caller's overrides")
+	}
+	overrides["load"].(map[string]interface{})["weight"] = 7.0
+	if cargo.args["load"].(map[string]interface{})["weight"] != 5.0 {
+		t.Error("changing the overrides after Instantiate changed the component")
+	}
+
+	after, _ := PrefabRegister.Get("crate")
+	if !reflect.DeepEqual(before, after) {
+		t.Errorf("Instantiate changed the prefab\n%+v\nwas\n%+v", after, before)
+	}
+	second, err := scene.Instantiate("crate", InstantiateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := second.Components()[0].(*testCargo).args
+	if args["load"].(map[string]interface{})["weight"] != 2.0 || args["items"].([]interface{})[1] != "lamp" {
+		t.Errorf("second instance got %v, want the prefab's arguments", args)
+	}
+	if !reflect.DeepEqual(second.Tags(), []string{"crate"}) || second.Layer() != DefaultLayer {
+		t.Errorf("second instance has tags %v and layer %s, want the prefab's", second.Tags(), second.Layer())
+	}
+}
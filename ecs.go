@@ -0,0 +1,572 @@
+package goldengine
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+//EntityID : Handle of an entity stored in a World. The handle of a despawned
+//entity stays invalid after its slot is reused. The zero EntityID is never an entity
+type EntityID uint64
+
+func newEntityID(index, generation uint32) EntityID {
+	return EntityID(uint64(generation)<<32 | uint64(index))
+}
+
+func (id EntityID) index() uint32 {
+	return uint32(id)
+}
+
+func (id EntityID) generation() uint32 {
+	return uint32(id >> 32)
+}
+
+//maxDataTypes : Most data types all Worlds together can store
+const maxDataTypes = 256
+
+//dataTypeID : Index of a data type in dataTypes
+type dataTypeID uint16
+
+//dataMask : Set of data types, one bit each
+type dataMask [maxDataTypes / 64]uint64
+
+func (m *dataMask) set(id dataTypeID) {
+	m[id/64] |= 1 << (id % 64)
+}
+
+func (m *dataMask) clear(id dataTypeID) {
+	m[id/64] &^= 1 << (id % 64)
+}
+
+func (m dataMask) has(id dataTypeID) bool {
+	return m[id/64]&(1<<(id%64)) != 0
+}
+
+//contains : Every type of o is in m
+func (m dataMask) contains(o dataMask) bool {
+	for i := range m {
+		if m[i]&o[i] != o[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//dataTypes : Every type stored in a World so far and how to make a column of it
+var dataTypes = struct {
+	sync.Mutex
+	ids     map[reflect.Type]dataTypeID
+	columns []func() column
+}{
+	ids: make(map[reflect.Type]dataTypeID),
+}
+
+//dataTypeOf : Id of T, given the first time T is stored
+func dataTypeOf[T any]() dataTypeID {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	dataTypes.Lock()
+	defer dataTypes.Unlock()
+	if id, ok := dataTypes.ids[t]; ok {
+		return id
+	}
+	if len(dataTypes.columns) == maxDataTypes {
+		panic(fmt.Errorf("Worlds can't store more than %d data types, %s is one too many", maxDataTypes, t))
+	}
+	id := dataTypeID(len(dataTypes.columns))
+	dataTypes.ids[t] = id
+	dataTypes.columns = append(dataTypes.columns, func() column {
+		return &typedColumn[T]{}
+	})
+	return id
+}
+
+func newColumn(id dataTypeID) column {
+	dataTypes.Lock()
+	build := dataTypes.columns[id]
+	dataTypes.Unlock()
+	return build()
+}
+
+//column : Values of one data type for every entity of an archetype, by row
+type column interface {
+	appendZero()
+	//appendFrom : Appends the value at row of src, a column of the same type
+	appendFrom(src column, row int)
+	//swapRemove : Removes a row by moving the last row into it
+	swapRemove(row int)
+}
+
+type typedColumn[T any] struct {
+	data []T
+}
+
+func (c *typedColumn[T]) appendZero() {
+	var zero T
+	c.data = append(c.data, zero)
+}
+
+func (c *typedColumn[T]) appendFrom(src column, row int) {
+	c.data = append(c.data, src.(*typedColumn[T]).data[row])
+}
+
+func (c *typedColumn[T]) swapRemove(row int) {
+	last := len(c.data) - 1
+	c.data[row] = c.data[last]
+	var zero T
+	c.data[last] = zero
+	c.data = c.data[:last]
+}
+
+//archetype : Every entity with exactly the same data types. Each type is a
+//column, each entity a row of every column
+type archetype struct {
+	mask     dataMask
+	columns  map[dataTypeID]column
+	entities []EntityID
+	//Archetypes with one type more or less, found so far
+	with    map[dataTypeID]*archetype
+	without map[dataTypeID]*archetype
+}
+
+//entityRecord : Where the data of an entity is
+type entityRecord struct {
+	generation uint32
+	alive      bool
+	archetype  *archetype
+	row        int
+}
+
+//archetypeQuery : Archetypes that have every type of a mask. Archetypes are
+//never removed, so only the ones made since the last call are checked
+type archetypeQuery struct {
+	checked int
+	matches []*archetype
+}
+
+//World : Entities made of plain data structs instead of Components. Entities
+//with the same data types are stored together in columns so systems go
+//through thousands of them without an interface call each. Every scene has
+//one, see Scene.World
+type World struct {
+	records    []entityRecord
+	free       []uint32
+	count      int
+	empty      *archetype
+	archetypes []*archetype
+	byMask     map[dataMask]*archetype
+	queries    map[dataMask]*archetypeQuery
+	systems    []WorldSystem
+	//Changes to where entities are stored wait while systems iterate
+	iterating int
+	pending   []func()
+}
+
+//NewWorld : Returns an empty World
+func NewWorld() *World {
+	w := &World{
+		byMask:  make(map[dataMask]*archetype),
+		queries: make(map[dataMask]*archetypeQuery),
+	}
+	w.empty = w.archetypeFor(dataMask{})
+	return w
+}
+
+//Len : Number of entities in the world
+func (w *World) Len() int {
+	return w.count
+}
+
+//Spawn : Adds an entity without data
+func (w *World) Spawn() EntityID {
+	return w.spawnInto(w.empty)
+}
+
+//Alive : The entity was spawned and not despawned
+func (w *World) Alive(id EntityID) bool {
+	return w.record(id) != nil
+}
+
+//Despawn : Removes an entity and its data. False if it isn't alive. While a
+//system iterates the entity is removed when the iteration ends
+func (w *World) Despawn(id EntityID) bool {
+	rec := w.record(id)
+	if rec == nil {
+		return false
+	}
+	if w.iterating > 0 {
+		w.pending = append(w.pending, func() {
+			w.Despawn(id)
+		})
+		return true
+	}
+	w.removeRow(rec.archetype, rec.row)
+	rec.alive = false
+	rec.archetype = nil
+	rec.generation++
+	if rec.generation == 0 {
+		rec.generation = 1
+	}
+	w.free = append(w.free, id.index())
+	w.count--
+	return true
+}
+
+//SetData : Gives an entity a value of type T, replacing the one it has. False
+//if the entity isn't alive. While a system iterates, a type the entity didn't
+//have yet is added when the iteration ends
+func SetData[T any](w *World, id EntityID, value T) bool {
+	rec := w.record(id)
+	if rec == nil {
+		return false
+	}
+	t := dataTypeOf[T]()
+	if col, ok := rec.archetype.columns[t]; ok {
+		col.(*typedColumn[T]).data[rec.row] = value
+		return true
+	}
+	if w.iterating > 0 {
+		w.pending = append(w.pending, func() {
+			SetData(w, id, value)
+		})
+		return true
+	}
+	w.move(id, rec, w.archetypeWith(rec.archetype, t))
+	rec.archetype.columns[t].(*typedColumn[T]).data[rec.row] = value
+	return true
+}
+
+//GetData : The entity's T. nil if it's not alive or has no T. The pointer is
+//valid until data types are added to or removed from any entity
+func GetData[T any](w *World, id EntityID) *T {
+	rec := w.record(id)
+	if rec == nil {
+		return nil
+	}
+	col, ok := rec.archetype.columns[dataTypeOf[T]()]
+	if !ok {
+		return nil
+	}
+	return &col.(*typedColumn[T]).data[rec.row]
+}
+
+//HasData : The entity is alive and has a T
+func HasData[T any](w *World, id EntityID) bool {
+	rec := w.record(id)
+	return rec != nil && rec.archetype.mask.has(dataTypeOf[T]())
+}
+
+//RemoveData : Takes the T off an entity. False if it didn't have one. While a
+//system iterates it's removed when the iteration ends
+func RemoveData[T any](w *World, id EntityID) bool {
+	rec := w.record(id)
+	if rec == nil {
+		return false
+	}
+	t := dataTypeOf[T]()
+	if !rec.archetype.mask.has(t) {
+		return false
+	}
+	if w.iterating > 0 {
+		w.pending = append(w.pending, func() {
+			RemoveData[T](w, id)
+		})
+		return true
+	}
+	w.move(id, rec, w.archetypeWithout(rec.archetype, t))
+	return true
+}
+
+//Spawn1 : Adds an entity with an A
+func Spawn1[A any](w *World, a A) EntityID {
+	if w.iterating > 0 {
+		id := w.Spawn()
+		SetData(w, id, a)
+		return id
+	}
+	ta := dataTypeOf[A]()
+	var mask dataMask
+	mask.set(ta)
+	arch := w.archetypeFor(mask)
+	id := w.spawnInto(arch)
+	row := len(arch.entities) - 1
+	arch.columns[ta].(*typedColumn[A]).data[row] = a
+	return id
+}
+
+//Spawn2 : Adds an entity with an A and a B. Faster than spawning and setting
+//them one by one since the entity is never moved
+func Spawn2[A, B any](w *World, a A, b B) EntityID {
+	ta, tb := dataTypeOf[A](), dataTypeOf[B]()
+	if w.iterating > 0 || ta == tb {
+		id := w.Spawn()
+		SetData(w, id, a)
+		SetData(w, id, b)
+		return id
+	}
+	var mask dataMask
+	mask.set(ta)
+	mask.set(tb)
+	arch := w.archetypeFor(mask)
+	id := w.spawnInto(arch)
+	row := len(arch.entities) - 1
+	arch.columns[ta].(*typedColumn[A]).data[row] = a
+	arch.columns[tb].(*typedColumn[B]).data[row] = b
+	return id
+}
+
+//Spawn3 : Adds an entity with an A, a B and a C
+func Spawn3[A, B, C any](w *World, a A, b B, c C) EntityID {
+	ta, tb, tc := dataTypeOf[A](), dataTypeOf[B](), dataTypeOf[C]()
+	if w.iterating > 0 || ta == tb || ta == tc || tb == tc {
+		id := w.Spawn()
+		SetData(w, id, a)
+		SetData(w, id, b)
+		SetData(w, id, c)
+		return id
+	}
+	var mask dataMask
+	mask.set(ta)
+	mask.set(tb)
+	mask.set(tc)
+	arch := w.archetypeFor(mask)
+	id := w.spawnInto(arch)
+	row := len(arch.entities) - 1
+	arch.columns[ta].(*typedColumn[A]).data[row] = a
+	arch.columns[tb].(*typedColumn[B]).data[row] = b
+	arch.columns[tc].(*typedColumn[C]).data[row] = c
+	return id
+}
+
+//Each1 : Calls fn with every entity that has an A, archetype by archetype in
+//the order they were made and entity by entity in the order they were stored
+func Each1[A any](w *World, fn func(id EntityID, a *A)) {
+	ta := dataTypeOf[A]()
+	var mask dataMask
+	mask.set(ta)
+	w.beginIteration()
+	defer w.endIteration()
+	for _, arch := range w.matching(mask) {
+		as := arch.columns[ta].(*typedColumn[A]).data
+		for i, id := range arch.entities {
+			fn(id, &as[i])
+		}
+	}
+}
+
+//Each2 : Calls fn with every entity that has an A and a B
+func Each2[A, B any](w *World, fn func(id EntityID, a *A, b *B)) {
+	ta, tb := dataTypeOf[A](), dataTypeOf[B]()
+	var mask dataMask
+	mask.set(ta)
+	mask.set(tb)
+	w.beginIteration()
+	defer w.endIteration()
+	for _, arch := range w.matching(mask) {
+		as := arch.columns[ta].(*typedColumn[A]).data
+		bs := arch.columns[tb].(*typedColumn[B]).data
+		for i, id := range arch.entities {
+			fn(id, &as[i], &bs[i])
+		}
+	}
+}
+
+//Each3 : Calls fn with every entity that has an A, a B and a C
+func Each3[A, B, C any](w *World, fn func(id EntityID, a *A, b *B, c *C)) {
+	ta, tb, tc := dataTypeOf[A](), dataTypeOf[B](), dataTypeOf[C]()
+	var mask dataMask
+	mask.set(ta)
+	mask.set(tb)
+	mask.set(tc)
+	w.beginIteration()
+	defer w.endIteration()
+	for _, arch := range w.matching(mask) {
+		as := arch.columns[ta].(*typedColumn[A]).data
+		bs := arch.columns[tb].(*typedColumn[B]).data
+		cs := arch.columns[tc].(*typedColumn[C]).data
+		for i, id := range arch.entities {
+			fn(id, &as[i], &bs[i], &cs[i])
+		}
+	}
+}
+
+//CountData : Number of entities with a T
+func CountData[T any](w *World) int {
+	var mask dataMask
+	mask.set(dataTypeOf[T]())
+	count := 0
+	for _, arch := range w.matching(mask) {
+		count += len(arch.entities)
+	}
+	return count
+}
+
+//WorldSystem : Logic run over the data of a World every update
+type WorldSystem interface {
+	Update(w *World, dur time.Duration)
+}
+
+//WorldSystemFunc : Function used as a WorldSystem
+type WorldSystemFunc func(w *World, dur time.Duration)
+
+//Update : Calls f
+func (f WorldSystemFunc) Update(w *World, dur time.Duration) {
+	f(w, dur)
+}
+
+//AddSystem : Runs a system every update, after the systems added before it
+func (w *World) AddSystem(system WorldSystem) {
+	w.systems = append(w.systems, system)
+}
+
+//Update : Runs every system in the order they were added
+func (w *World) Update(dur time.Duration) {
+	for _, system := range w.systems {
+		system.Update(w, dur)
+	}
+}
+
+//record : Record of a live entity, nil for dead or unknown ones
+func (w *World) record(id EntityID) *entityRecord {
+	index := id.index()
+	if int(index) >= len(w.records) {
+		return nil
+	}
+	rec := &w.records[index]
+	if !rec.alive || rec.generation != id.generation() {
+		return nil
+	}
+	return rec
+}
+
+//spawnInto : Adds an entity with zero values to an archetype
+func (w *World) spawnInto(arch *archetype) EntityID {
+	var index uint32
+	if n := len(w.free); n > 0 {
+		index = w.free[n-1]
+		w.free = w.free[:n-1]
+	} else {
+		index = uint32(len(w.records))
+		w.records = append(w.records, entityRecord{generation: 1})
+	}
+	rec := &w.records[index]
+	id := newEntityID(index, rec.generation)
+	rec.alive = true
+	rec.archetype = arch
+	rec.row = len(arch.entities)
+	arch.entities = append(arch.entities, id)
+	for _, col := range arch.columns {
+		col.appendZero()
+	}
+	w.count++
+	return id
+}
+
+//move : Moves an entity's data to another archetype. Types the new archetype
+//doesn't have are dropped, ones the old one didn't have are zero
+func (w *World) move(id EntityID, rec *entityRecord, to *archetype) {
+	from, row := rec.archetype, rec.row
+	for t, col := range to.columns {
+		if src, ok := from.columns[t]; ok {
+			col.appendFrom(src, row)
+		} else {
+			col.appendZero()
+		}
+	}
+	to.entities = append(to.entities, id)
+	w.removeRow(from, row)
+	rec.archetype = to
+	rec.row = len(to.entities) - 1
+}
+
+//removeRow : Removes a row of an archetype by moving its last entity into it
+func (w *World) removeRow(arch *archetype, row int) {
+	last := len(arch.entities) - 1
+	moved := arch.entities[last]
+	arch.entities[row] = moved
+	arch.entities = arch.entities[:last]
+	for _, col := range arch.columns {
+		col.swapRemove(row)
+	}
+	if row != last {
+		w.records[moved.index()].row = row
+	}
+}
+
+//archetypeFor : Archetype of exactly the types of mask, made if needed
+func (w *World) archetypeFor(mask dataMask) *archetype {
+	if arch, ok := w.byMask[mask]; ok {
+		return arch
+	}
+	arch := &archetype{
+		mask:    mask,
+		columns: make(map[dataTypeID]column),
+		with:    make(map[dataTypeID]*archetype),
+		without: make(map[dataTypeID]*archetype),
+	}
+	for t := 0; t < maxDataTypes; t++ {
+		if mask.has(dataTypeID(t)) {
+			arch.columns[dataTypeID(t)] = newColumn(dataTypeID(t))
+		}
+	}
+	w.byMask[mask] = arch
+	w.archetypes = append(w.archetypes, arch)
+	return arch
+}
+
+func (w *World) archetypeWith(from *archetype, t dataTypeID) *archetype {
+	if to, ok := from.with[t]; ok {
+		return to
+	}
+	mask := from.mask
+	mask.set(t)
+	to := w.archetypeFor(mask)
+	from.with[t] = to
+	return to
+}
+
+func (w *World) archetypeWithout(from *archetype, t dataTypeID) *archetype {
+	if to, ok := from.without[t]; ok {
+		return to
+	}
+	mask := from.mask
+	mask.clear(t)
+	to := w.archetypeFor(mask)
+	from.without[t] = to
+	return to
+}
+
+//matching : Archetypes with every type of mask, in the order they were made
+func (w *World) matching(mask dataMask) []*archetype {
+	query, ok := w.queries[mask]
+	if !ok {
+		query = &archetypeQuery{}
+		w.queries[mask] = query
+	}
+	for ; query.checked < len(w.archetypes); query.checked++ {
+		if arch := w.archetypes[query.checked]; arch.mask.contains(mask) {
+			query.matches = append(query.matches, arch)
+		}
+	}
+	return query.matches
+}
+
+func (w *World) beginIteration() {
+	w.iterating++
+}
+
+//endIteration : Applies the changes made during the outermost iteration
+func (w *World) endIteration() {
+	w.iterating--
+	if w.iterating > 0 {
+		return
+	}
+	for len(w.pending) > 0 {
+		pending := w.pending
+		w.pending = nil
+		for _, change := range pending {
+			change()
+		}
+	}
+}
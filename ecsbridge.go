@@ -0,0 +1,166 @@
+package goldengine
+
+import (
+	"sort"
+	"time"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//Position : Position of a World entity in pixels
+type Position sf.Vector2f
+
+//Velocity : Pixels a World entity moves every second
+type Velocity sf.Vector2f
+
+//Renderable : Drawn by the scene at the entity's Position, sorted with the
+//scene's Entities by layer and z index. Many entities can share one Drawer
+type Renderable struct {
+	Drawer sf.Drawer
+	ZIndex int
+	//Layer : DefaultLayer when empty
+	Layer string
+}
+
+//EntityLink : Data of a World entity standing for an Entity of the scene.
+//See Scene.Link
+type EntityLink struct {
+	Entity *Entity
+}
+
+//MoveSystem : Moves every World entity with a Position by its Velocity
+var MoveSystem = WorldSystemFunc(func(w *World, dur time.Duration) {
+	seconds := float32(dur.Seconds())
+	Each2(w, func(id EntityID, p *Position, v *Velocity) {
+		p.X += v.X * seconds
+		p.Y += v.Y * seconds
+	})
+})
+
+//World : Data oriented entities of the scene. Its systems run every update
+//after the components of the scene's Entities
+func (s *Scene) World() *World {
+	if s.world == nil {
+		s.world = NewWorld()
+	}
+	return s.world
+}
+
+//Link : World entity of an Entity of the scene, spawned the first time. It has
+//an EntityLink and a Position that follows the entity's world position, so
+//systems can read and move the entity. It's despawned with the entity. 0 if
+//the entity isn't in the scene
+func (s *Scene) Link(e *Entity) EntityID {
+	if e.scene != s {
+		return 0
+	}
+	w := s.World()
+	if w.Alive(e.ecs) {
+		return e.ecs
+	}
+	position := e.WorldPosition()
+	e.ecs = Spawn2(w, EntityLink{Entity: e}, Position(position))
+	e.ecsPosition = position
+	return e.ecs
+}
+
+//LinkedEntity : Entity a World entity of the scene stands for
+func (s *Scene) LinkedEntity(id EntityID) (*Entity, bool) {
+	if s.world == nil {
+		return nil, false
+	}
+	link := GetData[EntityLink](s.world, id)
+	if link == nil || link.Entity == nil {
+		return nil, false
+	}
+	return link.Entity, true
+}
+
+//unlink : Despawns the World entity of an Entity leaving the scene
+func (s *Scene) unlink(e *Entity) {
+	if s.world != nil && e.ecs != 0 {
+		s.world.Despawn(e.ecs)
+	}
+	e.ecs = 0
+}
+
+//updateWorld : Runs the World's systems. Linked entities that moved since the
+//last update move their Position first, and Positions systems changed move
+//their entity after
+func (s *Scene) updateWorld(dur time.Duration) {
+	if s.world == nil {
+		return
+	}
+	profiler := s.profiler()
+	start := profiler.now()
+	defer profiler.end("ecs", "World "+s.Name, start, profileLoopThread)
+	Each2(s.world, func(id EntityID, link *EntityLink, p *Position) {
+		if position := link.Entity.WorldPosition(); position != link.Entity.ecsPosition {
+			*p = Position(position)
+			link.Entity.ecsPosition = position
+		}
+	})
+	s.world.Update(dur)
+	Each2(s.world, func(id EntityID, link *EntityLink, p *Position) {
+		e := link.Entity
+		if sf.Vector2f(*p) == e.ecsPosition || e.destroyed || !e.ActiveInHierarchy() {
+			return
+		}
+		e.SetWorldPosition(sf.Vector2f(*p))
+		e.ecsPosition = sf.Vector2f(*p)
+		if e.Collider != nil {
+			e.Collider.SetPosition(Vector2fToChipmunk(e.ecsPosition))
+			e.storeBodyState()
+		}
+	})
+}
+
+//worldDrawable : Renderable of a World entity ready to be drawn
+type worldDrawable struct {
+	drawer sf.Drawer
+	layer  int
+	zIndex int
+	matrix Matrix
+}
+
+//before : Drawn before a node. Nodes with the same layer and z index are drawn first
+func (d worldDrawable) before(node *entityNode) bool {
+	layer := node.layerOrder()
+	if d.layer != layer {
+		return d.layer < layer
+	}
+	return d.zIndex < node.zIndex
+}
+
+//worldDrawables : Renderables of the World sorted by layer and z index, in the
+//order systems see them otherwise
+func (s *Scene) worldDrawables() []worldDrawable {
+	s.drawables = s.drawables[:0]
+	if s.world == nil {
+		return s.drawables
+	}
+	Each2(s.world, func(id EntityID, r *Renderable, p *Position) {
+		if r.Drawer == nil {
+			return
+		}
+		s.drawables = append(s.drawables, worldDrawable{
+			drawer: r.Drawer,
+			layer:  LayerRegister.DrawOrder(r.Layer),
+			zIndex: r.ZIndex,
+			matrix: Matrix{A: 1, C: p.X, E: 1, F: p.Y},
+		})
+	})
+	sort.SliceStable(s.drawables, func(i, j int) bool {
+		a, b := s.drawables[i], s.drawables[j]
+		if a.layer != b.layer {
+			return a.layer < b.layer
+		}
+		return a.zIndex < b.zIndex
+	})
+	return s.drawables
+}
+
+func (d worldDrawable) draw(target Renderer, renderStates sf.RenderStates) {
+	renderStates.Transform = MatrixFromSFML(renderStates.Transform).Multiply(d.matrix).ToSFML()
+	target.Draw(d.drawer, renderStates)
+}
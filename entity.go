@@ -2,11 +2,14 @@ package goldengine
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"sync/atomic"
 	"time"
 
+	sf "github.com/manyminds/gosfml"
 	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
 )
 
 //EntityPrefab : Information Required to Create an Entity from JSON Prefab
@@ -15,6 +18,9 @@ type EntityPrefab struct {
 	Components  []ComponentPrefab
 	Transformer TransformerPrefab
 	Collider    ColliderPrefab
+	Tags        []string `json:",omitempty"`
+	//Layer : DefaultLayer when empty
+	Layer string `json:",omitempty"`
 }
 
 //Entity : Entity
@@ -26,15 +32,42 @@ type Entity struct {
 	parent      *Entity
 	children    map[uint32]*Entity
 	components  []Component
-	Transfrom   Transformer
-	Collider    *chipmunk.Body
-	scene       *Scene
-	started     bool
-	awake       bool
+	//Move it with SetPosition, Move, SetRotation and SetScale, or call
+	//MarkTransformDirty after changing it directly
+	Transfrom Transformer
+	Collider  *chipmunk.Body
+	scene     *Scene
+	prefab    string
+	started   bool
+	awake     bool
+	destroyed bool
+	disabled  bool
+	//Registered names of components, "" for ones added in code
+	componentNames []string
+	world          worldCache
+	//Layer name, "" for DefaultLayer, and the LayerRegister version its collider has
+	layer        string
+	layerVersion uint64
+	//World entity linked to this one and the world position it was last synced at
+	ecs         EntityID
+	ecsPosition sf.Vector2f
+	//Position in the update order of the component updating the entity's tree
+	//in parallel, or that destroyed the entity
+	updateSlot int
+	//Timers made before the entity was added to a scene
+	timers []*Timer
+	//Waiting in its scene's moved list for the spatial index
+	spatialQueued bool
+	//Collider state at the start of the last physics step
+	lastBodyPosition vect.Vect
+	lastBodyAngle    vect.Float
 }
 
 //Start : Called before gameloop
 func (e *Entity) Start() {
+	if e.started {
+		return
+	}
 	e.started = true
 	for _, c := range e.components {
 		c.Start()
@@ -43,6 +76,9 @@ func (e *Entity) Start() {
 
 //Awake : Called After Start and after sleep
 func (e *Entity) Awake() {
+	if e.awake {
+		return
+	}
 	e.awake = true
 	for _, c := range e.components {
 		c.Awake()
@@ -51,25 +87,200 @@ func (e *Entity) Awake() {
 
 //Update : Called during the gameloop
 func (e *Entity) Update(dur time.Duration) {
+	profiler := e.scene.profiler()
 	for _, c := range e.components {
+		start := profiler.now()
 		c.Update(dur)
+		profiler.component(c, start)
+	}
+}
+
+//Interpolate : Called before drawing with how far the game is between the
+//last fixed update and the next one
+func (e *Entity) Interpolate(alpha float32) {
+	for _, c := range e.components {
+		if i, ok := c.(Interpolator); ok {
+			i.Interpolate(alpha)
+		}
+	}
+}
+
+//storeBodyState : Remembers the collider state before a physics step
+func (e *Entity) storeBodyState() {
+	e.lastBodyPosition = e.Collider.Position()
+	e.lastBodyAngle = e.Collider.Angle()
+}
+
+//interpolateBody : Places the transform between the last and current collider state
+func (e *Entity) interpolateBody(alpha float32) {
+	if e.Collider == nil || e.Transfrom == nil {
+		return
+	}
+	a := vect.Float(alpha)
+	pos := e.Collider.Position()
+	pos.X = e.lastBodyPosition.X + (pos.X-e.lastBodyPosition.X)*a
+	pos.Y = e.lastBodyPosition.Y + (pos.Y-e.lastBodyPosition.Y)*a
+	angle := e.lastBodyAngle + (e.Collider.Angle()-e.lastBodyAngle)*a
+	rotation := float32(angle) * 180 / math.Pi
+	if e.parent != nil {
+		rotation -= e.parent.WorldRotation()
 	}
+	e.SetWorldPosition(ChipmunkToVector2f(pos))
+	e.SetRotation(rotation)
 }
 
-//Sleep : Shouldn't Update Entity Anymore
+//Sleep : Shouldn't Update Entity Anymore. Components sleep after the ones that require them
 func (e *Entity) Sleep() {
+	if !e.awake {
+		return
+	}
 	e.awake = false
-	for _, c := range e.components {
-		c.Sleep()
+	for i := len(e.components) - 1; i >= 0; i-- {
+		e.components[i].Sleep()
 	}
 }
 
-//Stop : Shouldn't Update Entity Anymore
+//Stop : Entity is being destroyed. Sleeps it first if it's awake.
+//Components stop after the ones that require them. Its timers are
+//cancelled even if it never started
 func (e *Entity) Stop() {
+	e.cancelTimers()
+	if !e.started {
+		return
+	}
+	e.Sleep()
 	e.started = false
-	for _, c := range e.components {
-		c.Stop()
+	for i := len(e.components) - 1; i >= 0; i-- {
+		e.components[i].Stop()
+	}
+}
+
+//Destroy : Stops updating the entity and its children and removes them from
+//their scene at the end of the frame. Without a scene they're stopped right away
+func (e *Entity) Destroy() {
+	if e.destroyed {
+		return
+	}
+	e.markDestroyed()
+	if e.scene == nil {
+		e.stopTree()
+		return
+	}
+	root := e
+	for root.parent != nil {
+		root = root.parent
+	}
+	e.updateSlot = root.updateSlot
+	e.scene.destroyLock.Lock()
+	e.scene.destroyed = append(e.scene.destroyed, e)
+	e.scene.destroyLock.Unlock()
+}
+
+//IsDestroyed : Destroy was called on the entity or one of its parents
+func (e *Entity) IsDestroyed() bool {
+	return e.destroyed
+}
+
+func (e *Entity) markDestroyed() {
+	e.destroyed = true
+	for _, child := range e.children {
+		child.markDestroyed()
+	}
+}
+
+//stopTree : Stops children before their parent
+func (e *Entity) stopTree() {
+	for _, child := range e.children {
+		child.stopTree()
+	}
+	e.Stop()
+}
+
+//SetEnabled : Enables or disables the entity and its children. Disabled
+//entities don't update, draw or collide and their components sleep until
+//the entity is enabled again
+func (e *Entity) SetEnabled(enabled bool) {
+	if e.disabled != enabled {
+		return
+	}
+	wasActive := e.ActiveInHierarchy()
+	e.disabled = !enabled
+	e.activeChanged(wasActive)
+}
+
+//IsEnabled : SetEnabled wasn't used to disable the entity. Its parents may still be disabled
+func (e *Entity) IsEnabled() bool {
+	return !e.disabled
+}
+
+//ActiveInHierarchy : Entity and all its parents are enabled
+func (e *Entity) ActiveInHierarchy() bool {
+	for p := e; p != nil; p = p.parent {
+		if p.disabled {
+			return false
+		}
+	}
+	return true
+}
+
+//activeChanged : Sleeps or wakes the subtree if ActiveInHierarchy changed from wasActive
+func (e *Entity) activeChanged(wasActive bool) {
+	switch active := e.ActiveInHierarchy(); {
+	case active && !wasActive:
+		e.activate()
+	case !active && wasActive:
+		e.deactivate()
+	}
+}
+
+//deactivate : Sleeps the subtree children first and takes it out of physics
+func (e *Entity) deactivate() {
+	for _, child := range e.sortedChildren() {
+		if !child.disabled {
+			child.deactivate()
+		}
+	}
+	e.Sleep()
+	if e.scene != nil {
+		e.scene.PostMessage(Message{
+			Message: SceneDisabledEntityMSG,
+			Content: e,
+		})
+	}
+}
+
+//activate : Puts the subtree back in physics and wakes it parents first if the scene is awake
+func (e *Entity) activate() {
+	if e.scene != nil {
+		e.scene.PostMessage(Message{
+			Message: SceneEnabledEntityMSG,
+			Content: e,
+		})
+		if e.scene.woken {
+			e.Start()
+			e.Awake()
+		}
+	}
+	for _, child := range e.sortedChildren() {
+		if !child.disabled {
+			child.activate()
+		}
+	}
+}
+
+//sortedChildren : Children in the order they were created
+func (e *Entity) sortedChildren() []*Entity {
+	list := make([]*Entity, 0, len(e.children))
+	for _, child := range e.children {
+		list = append(list, child)
 	}
+	sortEntities(list)
+	return list
+}
+
+//GetScene : Scene this entity belongs to
+func (e *Entity) GetScene() *Scene {
+	return e.scene
 }
 
 //AddChild : Adds A child to the entity
@@ -79,20 +290,133 @@ func (e *Entity) AddChild(child *Entity) {
 	}
 	child.parent = e
 	e.children[child.id] = child
+	child.MarkTransformDirty()
 }
 
 //RemoveChild : Removes Child Enttiy
 func (e *Entity) RemoveChild(child *Entity) {
 	child.parent = nil
 	delete(e.children, child.id)
+	child.MarkTransformDirty()
+}
+
+//AddComponent : Associates a component with a given entity. Components it
+//requires that the entity doesn't have yet are added before it from
+//ComponentRegister. Errors if a requirement can't be met
+func (e *Entity) AddComponent(comp Component) error {
+	return e.addNamedComponent("", comp)
+}
+
+//Components : Components of the entity in the order they were added
+func (e *Entity) Components() []Component {
+	list := make([]Component, len(e.components))
+	copy(list, e.components)
+	return list
 }
 
-//AddComponent : Associates a component with a given entity
-func (e *Entity) AddComponent(comp Component) {
-	if comp != nil {
-		e.components = append(e.components, comp)
-		comp.SetEntity(e)
+//RemoveComponent : Takes a component off the entity. It's put to sleep and
+//stopped first if the entity is awake or started. False if it wasn't on the entity
+func (e *Entity) RemoveComponent(comp Component) bool {
+	for i, c := range e.components {
+		if c != comp {
+			continue
+		}
+		if e.awake {
+			c.Sleep()
+		}
+		if e.started {
+			c.Stop()
+		}
+		e.components = append(e.components[:i], e.components[i+1:]...)
+		e.componentNames = append(e.componentNames[:i], e.componentNames[i+1:]...)
+		if e.scene != nil {
+			e.scene.invalidateOrder()
+		}
+		return true
+	}
+	return false
+}
+
+//addNamedComponent : AddComponent remembering the name the component was registered with
+func (e *Entity) addNamedComponent(name string, comp Component) error {
+	if comp == nil {
+		return nil
+	}
+	if err := e.addRequirements(name, comp, map[string]bool{name: true}); err != nil {
+		return err
+	}
+	e.components = append(e.components, comp)
+	e.componentNames = append(e.componentNames, name)
+	comp.SetEntity(e)
+	if e.scene != nil {
+		e.scene.invalidateOrder()
 	}
+	return nil
+}
+
+//addRequirements : Adds the components comp requires that the entity is missing,
+//their own requirements first
+func (e *Entity) addRequirements(name string, comp Component, resolving map[string]bool) error {
+	label := name
+	if label == "" {
+		label = fmt.Sprintf("%T", comp)
+	}
+	for _, required := range componentRequirements(name, comp) {
+		switch required {
+		case RequireCollider:
+			if e.Collider == nil {
+				return fmt.Errorf("Component %s on %s requires a Collider", label, e.Name)
+			}
+			continue
+		case RequireTransformer:
+			if e.Transfrom == nil {
+				return fmt.Errorf("Component %s on %s requires a Transformer", label, e.Name)
+			}
+			continue
+		}
+		if e.hasComponentNamed(required) {
+			continue
+		}
+		if resolving[required] {
+			return fmt.Errorf("Component %s on %s requires %s, which requires it back", label, e.Name, required)
+		}
+		if _, ok := ComponentRegister.Get(required); !ok {
+			return fmt.Errorf("Component %s on %s requires %s, which isn't registered", label, e.Name, required)
+		}
+		dependency, err := ComponentRegister.build(required, make(map[string]interface{}))
+		if err != nil {
+			return fmt.Errorf("Component %s on %s requires %s: %v", label, e.Name, required, err)
+		}
+		resolving[required] = true
+		if err := e.addRequirements(required, dependency, resolving); err != nil {
+			return err
+		}
+		delete(resolving, required)
+		e.components = append(e.components, dependency)
+		e.componentNames = append(e.componentNames, required)
+		dependency.SetEntity(e)
+	}
+	return nil
+}
+
+//hasComponent : Component is still on the entity
+func (e *Entity) hasComponent(comp Component) bool {
+	for _, c := range e.components {
+		if c == comp {
+			return true
+		}
+	}
+	return false
+}
+
+//hasComponentNamed : Entity has a component created from ComponentRegister with that name
+func (e *Entity) hasComponentNamed(name string) bool {
+	for _, n := range e.componentNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
 }
 
 //RecalculateScale : Changes the size of sfml and chipmunk objects
@@ -113,29 +437,42 @@ func NewEntity() *Entity {
 	}
 }
 
-//EntityFromEntityPrefab : Returns Entity from Entity Prefab
-func EntityFromEntityPrefab(prefab EntityPrefab) *Entity {
+//EntityFromEntityPrefab : Returns Entity from Entity Prefab. Errors if the
+//transformer, collider or component arguments are wrong or a component's
+//requirements can't be met
+func EntityFromEntityPrefab(prefab EntityPrefab) (*Entity, error) {
 	e := NewEntity()
 	e.Name = prefab.Name
+	e.prefab = prefab.Name
 	var err error
 	e.Transfrom, err = TransformerFromTranformerPrefab(prefab.Transformer)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("Prefab %s, transformer: %v", prefab.Name, err)
 	}
 	if prefab.Collider.Kind != "" {
 		fmt.Println("Getting Collider")
 		e.Collider, err = ColliderFromColliderPrefab(prefab.Collider)
 		fmt.Println(e.Collider)
 		if err != nil {
-			panic(err)
+			return nil, fmt.Errorf("Prefab %s, collider: %v", prefab.Name, err)
 		}
 	}
+	e.addTags(prefab.Tags)
+	if prefab.Layer != "" {
+		e.SetLayer(prefab.Layer)
+	}
 	e.components = make([]Component, 0)
-	for _, p := range prefab.Components {
-
-		comp := ComponentFromComponentPrefab(p)
-		e.AddComponent(comp)
-
+	comps := make([]Component, len(prefab.Components))
+	for i, p := range prefab.Components {
+		comps[i], err = ComponentRegister.build(p.Name, p.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("Prefab %s, component %s: %v", prefab.Name, p.Name, err)
+		}
+	}
+	for _, i := range orderPrefabComponents(prefab.Components, comps) {
+		if err := e.addNamedComponent(prefab.Components[i].Name, comps[i]); err != nil {
+			return nil, fmt.Errorf("Prefab %s: %v", prefab.Name, err)
+		}
 	}
-	return e
+	return e, nil
 }
@@ -0,0 +1,160 @@
+package goldengine
+
+import (
+	"testing"
+	"time"
+)
+
+//testLife : Counts the lifecycle calls of its entity and runs update on Update
+type testLife struct {
+	BaseComponent
+	starts, awakes, updates, sleeps, stops int
+	update                                 func()
+}
+
+func (c *testLife) Start() { c.starts++ }
+func (c *testLife) Awake() { c.awakes++ }
+func (c *testLife) Sleep() { c.sleeps++ }
+func (c *testLife) Stop()  { c.stops++ }
+
+func (c *testLife) Update(dur time.Duration) {
+	c.updates++
+	if c.update != nil {
+		c.update()
+	}
+}
+
+//newLifeEntity : Entity named name with a testLife
+func newLifeEntity(name string) (*Entity, *testLife) {
+	e := NewEntity()
+	e.Name = name
+	life := &testLife{}
+	e.AddComponent(life)
+	return e, life
+}
+
+//newEmptySceneGame : Test game whose current scene, main, has no entities
+func newEmptySceneGame(t *testing.T) (*Game, *Scene) {
+	t.Helper()
+	game := newTestGame(t, GameConfig{TimeStep: 10 * time.Millisecond}, nil, map[string]string{
+		"scenes/main.scene": `{"Name":"main"}`,
+	})
+	game.ChangeScene("main")
+	return game, game.GetCurrentScene()
+}
+
+func TestDestroyRemovesAtEndOfFrame(t *testing.T) {
+	game, scene := newEmptySceneGame(t)
+	first, firstLife := newLifeEntity("first")
+	doomed, doomedLife := newLifeEntity("doomed")
+	doomedChild, childLife := newLifeEntity("doomedChild")
+	last, lastLife := newLifeEntity("last")
+	doomed.AddChild(doomedChild)
+	for _, e := range []*Entity{first, doomed, last} {
+		scene.AddEntity(e)
+	}
+	game.Start()
+	defer game.Stop()
+
+	firstLife.update = func() {
+		if doomed.IsDestroyed() {
+			return
+		}
+		doomed.Destroy()
+		if !doomedChild.IsDestroyed() {
+			t.Error("destroying an entity didn't destroy its child")
+		}
+		if _, ok := scene.GetEntityByName("doomed"); !ok {
+			t.Error("destroyed entity was removed before the end of the frame")
+		}
+		if doomedLife.stops != 0 {
+			t.Error("destroyed entity stopped before the end of the frame")
+		}
+	}
+	lastLife.update = func() {
+		if _, ok := scene.GetEntityByName("doomed"); !ok {
+			t.Error("destroyed entity was removed while components were updating")
+		}
+	}
+	game.Tick(10 * time.Millisecond)
+
+	if doomedLife.updates != 0 || childLife.updates != 0 {
+		t.Errorf("destroyed entities updated %d and %d times after Destroy", doomedLife.updates, childLife.updates)
+	}
+	if lastLife.updates != 1 {
+		t.Errorf("entity after the destroyed one updated %d times, want 1", lastLife.updates)
+	}
+	for _, name := range []string{"doomed", "doomedChild"} {
+		if _, ok := scene.GetEntityByName(name); ok {
+			t.Errorf("%s is still in the scene after the frame", name)
+		}
+	}
+	if doomedLife.sleeps != 1 || doomedLife.stops != 1 || childLife.stops != 1 {
+		t.Errorf("destroyed entity slept %d and stopped %d times, child stopped %d, want 1 1 1",
+			doomedLife.sleeps, doomedLife.stops, childLife.stops)
+	}
+	if doomed.GetScene() != nil {
+		t.Error("removed entity still points at the scene")
+	}
+}
+
+func TestEnabledTogglesSubtreeOnce(t *testing.T) {
+	game, scene := newEmptySceneGame(t)
+	parent, parentLife := newLifeEntity("parent")
+	child, childLife := newLifeEntity("child")
+	grandchild, grandchildLife := newLifeEntity("grandchild")
+	parent.AddChild(child)
+	child.AddChild(grandchild)
+	scene.AddEntity(parent)
+	game.Start()
+	defer game.Stop()
+	lives := []*testLife{parentLife, childLife, grandchildLife}
+	check := func(step string, awakes, sleeps int) {
+		t.Helper()
+		for i, life := range lives {
+			if life.awakes != awakes || life.sleeps != sleeps {
+				t.Errorf("%s: entity %d woke %d and slept %d times, want %d and %d",
+					step, i, life.awakes, life.sleeps, awakes, sleeps)
+			}
+		}
+	}
+	check("start", 1, 0)
+
+	parent.SetEnabled(false)
+	check("disable", 1, 1)
+	parent.SetEnabled(false)
+	check("disable again", 1, 1)
+	if child.ActiveInHierarchy() || !child.IsEnabled() {
+		t.Error("child of a disabled parent is active or disabled itself")
+	}
+	game.Tick(10 * time.Millisecond)
+	for i, life := range lives {
+		if life.updates != 0 {
+			t.Errorf("entity %d of a disabled tree updated", i)
+		}
+	}
+
+	parent.SetEnabled(true)
+	check("enable", 2, 1)
+	parent.SetEnabled(true)
+	check("enable again", 2, 1)
+	if !grandchild.ActiveInHierarchy() {
+		t.Error("grandchild isn't active once its parents are enabled")
+	}
+
+	//A child disabled on its own stays asleep while its parent toggles
+	child.SetEnabled(false)
+	parent.SetEnabled(false)
+	parent.SetEnabled(true)
+	if parentLife.awakes != 3 || parentLife.sleeps != 2 {
+		t.Errorf("parent woke %d and slept %d times, want 3 and 2", parentLife.awakes, parentLife.sleeps)
+	}
+	for _, life := range lives[1:] {
+		if life.awakes != 2 || life.sleeps != 2 {
+			t.Errorf("disabled child woke %d and slept %d times, want 2 and 2", life.awakes, life.sleeps)
+		}
+	}
+	if child.ActiveInHierarchy() {
+		t.Error("disabled child is active")
+	}
+}
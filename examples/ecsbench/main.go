@@ -0,0 +1,166 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	GE "github.com/Dacode45/goldengine"
+	sf "github.com/manyminds/gosfml"
+)
+
+//MoverConfig : Arguments of the mover prefab
+type MoverConfig struct {
+	VelocityX float32 `ge:"velocityX,default=30"`
+	VelocityY float32 `ge:"velocityY,default=10"`
+}
+
+//MoverComponent : Moves its entity's transform by its velocity every update,
+//the way a bullet written as a Component would
+type MoverComponent struct {
+	VelocityX, VelocityY float32
+	GE.BaseComponent
+}
+
+//NewMoverComponent : Builds a Mover from its config
+func NewMoverComponent(config MoverConfig) GE.Component {
+	return &MoverComponent{
+		VelocityX: config.VelocityX,
+		VelocityY: config.VelocityY,
+	}
+}
+
+//Update : Moves the mover's entity
+func (m *MoverComponent) Update(dur time.Duration) {
+	seconds := float32(dur.Seconds())
+	m.GetEntity().Move(sf.Vector2f{
+		X: m.VelocityX * seconds,
+		Y: m.VelocityY * seconds,
+	})
+}
+
+//UpdateAccess : Movers only touch their own entity so they can update in parallel
+func (m *MoverComponent) UpdateAccess() GE.Access {
+	return GE.Access{}
+}
+
+func init() {
+	if err := GE.RegisterConfigured("mover", NewMoverComponent); err != nil {
+		panic(err)
+	}
+}
+
+const prefab = `{"Name":"mover","Components":[{"Name":"mover"}],
+	"Transformer":{"Kind":"RectangleShape","Arguments":{}}}`
+
+const scene = `{"Name":"bench","Entities":[]}`
+
+//newGame : Headless game showing an empty bench scene
+func newGame(dir string) (*GE.Game, *GE.Scene) {
+	folders := map[string]string{
+		"prefabs":   filepath.Join(dir, "prefabs"),
+		"resources": filepath.Join(dir, "resources"),
+		"scenes":    filepath.Join(dir, "scenes"),
+	}
+	for _, folder := range folders {
+		if err := os.MkdirAll(folder, 0755); err != nil {
+			panic(err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(folders["prefabs"], "mover.json"): prefab,
+		filepath.Join(folders["scenes"], "bench.scene"): scene,
+	}
+	for path, content := range files {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			panic(err)
+		}
+	}
+	game := GE.NewGame(GE.GameConfig{
+		Name:                "ECS Bench",
+		LogFile:             ioutil.Discard,
+		PrefabsFolderName:   folders["prefabs"],
+		ResourcesFolderName: folders["resources"],
+		ScenesFolderName:    folders["scenes"],
+	}, GE.WindowConfig{
+		Backend: GE.NewHeadlessBackend(800, 600),
+	}, GE.PhysicsEngineConfig{})
+	game.Init()
+	game.ChangeScene("bench")
+	game.Start()
+	game.Advance(0)
+	return game, game.GetCurrentScene()
+}
+
+//addComponentMovers : Adds n movers written as Components to the scene
+func addComponentMovers(scene *GE.Scene, n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := scene.Instantiate("mover", GE.InstantiateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//addWorldMovers : Adds n movers to the scene's World, moved by MoveSystem
+func addWorldMovers(scene *GE.Scene, n int) {
+	world := scene.World()
+	world.AddSystem(GE.MoveSystem)
+	for i := 0; i < n; i++ {
+		GE.Spawn2(world, GE.Position{}, GE.Velocity{X: 30, Y: 10})
+	}
+}
+
+//timeUpdates : Average time of a fixed update of a new game whose scene add fills
+func timeUpdates(n, frames, workers int, add func(scene *GE.Scene, n int) error) (time.Duration, error) {
+	dir, err := ioutil.TempDir("", "ecsbench")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(dir)
+	game, scene := newGame(dir)
+	defer game.Stop()
+	scene.SetUpdateWorkers(workers)
+	if err := add(scene, n); err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	for i := 0; i < frames; i++ {
+		game.Advance(game.TimeStep())
+	}
+	return time.Since(start) / time.Duration(frames), nil
+}
+
+//Compares updating entities written as Components, serially and in parallel,
+//with entities stored in a World, per entity. go test -bench . runs the
+//benchmarks this gives a quick look at
+func main() {
+	n := flag.Int("n", 10000, "Entities updated every fixed update")
+	frames := flag.Int("frames", 100, "Fixed updates timed")
+	flag.Parse()
+	world := func(scene *GE.Scene, n int) error {
+		addWorldMovers(scene, n)
+		return nil
+	}
+	runs := []struct {
+		name    string
+		workers int
+		add     func(scene *GE.Scene, n int) error
+	}{
+		{"components", 1, addComponentMovers},
+		{"parallel", GE.DefaultUpdateWorkers(), addComponentMovers},
+		{"world", 1, world},
+	}
+	for _, run := range runs {
+		perUpdate, err := timeUpdates(*n, *frames, run.workers, run.add)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		perEntity := float64(perUpdate.Nanoseconds()) / float64(*n)
+		fmt.Printf("%-12s %8d entities %12d ns/update %8.2f ns/entity\n", run.name, *n, perUpdate.Nanoseconds(), perEntity)
+	}
+}
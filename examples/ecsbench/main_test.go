@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+
+	GE "github.com/Dacode45/goldengine"
+)
+
+//benchEntities : Entities updated or spawned by every benchmark
+const benchEntities = 10000
+
+//benchComponents : Fixed updates of movers written as Components, on workers goroutines
+func benchComponents(b *testing.B, workers int) {
+	game, scene := newGame(b.TempDir())
+	defer game.Stop()
+	scene.SetUpdateWorkers(workers)
+	if err := addComponentMovers(scene, benchEntities); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		game.Advance(game.TimeStep())
+	}
+}
+
+func BenchmarkComponents(b *testing.B) {
+	benchComponents(b, 1)
+}
+
+func BenchmarkComponentsParallel(b *testing.B) {
+	benchComponents(b, GE.DefaultUpdateWorkers())
+}
+
+func BenchmarkWorld(b *testing.B) {
+	game, scene := newGame(b.TempDir())
+	defer game.Stop()
+	addWorldMovers(scene, benchEntities)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		game.Advance(game.TimeStep())
+	}
+}
+
+func BenchmarkSpawn(b *testing.B) {
+	world := GE.NewWorld()
+	ids := make([]GE.EntityID, benchEntities)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range ids {
+			ids[j] = GE.Spawn2(world, GE.Position{}, GE.Velocity{X: 30, Y: 10})
+		}
+		for _, id := range ids {
+			world.Despawn(id)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	GE "github.com/Dacode45/goldengine"
+	sf "github.com/manyminds/gosfml"
+)
+
+var update = flag.Bool("update", false, "Write the golden images again from what the tests render")
+
+func TestRenderMainScene(t *testing.T) {
+	clearColor := sf.Color{R: 50, G: 200, B: 50, A: 255}
+	//pong has no resources, testdata stands in for the folder
+	app := GE.NewGame(GE.GameConfig{
+		Name:                "Test",
+		LogFile:             ioutil.Discard,
+		ResourcesFolderName: "testdata",
+	}, GE.WindowConfig{
+		Width:      800,
+		Height:     600,
+		ClearColor: clearColor,
+		Backend:    GE.NewSoftwareBackend(800, 600),
+	}, GE.PhysicsEngineConfig{})
+	app.Init()
+	app.ChangeScene("main")
+	app.Start()
+	defer app.Stop()
+	app.Advance(0)
+
+	got := GE.RenderSceneToImage(app.GetCurrentScene(), 800, 600, clearColor)
+	golden := filepath.Join("testdata", "main.png")
+	if *update {
+		if err := GE.SavePNG(got, golden); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := GE.LoadPNG(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := GE.CompareImages(got, want, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff > 0 {
+		t.Errorf("%d pixels differ from %s, run go test -update to accept them", diff, golden)
+	}
+}
@@ -9,7 +9,15 @@ import (
 )
 
 func init() {
-	GE.ComponentRegister.Register("paddle", NewPaddleComponent)
+	err := GE.RegisterConfigured("paddle", NewPaddleComponent, GE.RequireTransformer)
+	if err != nil {
+		panic(err)
+	}
+}
+
+//PaddleConfig : Arguments of the paddle prefab
+type PaddleConfig struct {
+	Speed float32 `ge:"speed,default=400,min=0"`
 }
 
 //PaddleComponent : Moves the Paddle Up and Down when Up and down keys are pressed
@@ -20,22 +28,16 @@ type PaddleComponent struct {
 	GE.BaseComponent
 }
 
-//NewPaddleComponent : ComponentGenerator for Paddle
-func NewPaddleComponent(args map[string]interface{}) GE.Component {
+//NewPaddleComponent : Builds a Paddle from its config
+func NewPaddleComponent(config PaddleConfig) GE.Component {
 	comp := PaddleComponent{
+		Speed:      config.Speed,
 		keyHandler: GE.GenInputHandler(),
 	}
 	comp.keyHandler.RegisterKeyPressedCommand(sf.KeyUp, comp.MoveUp)
 	comp.keyHandler.RegisterKeyPressedCommand(sf.KeyDown, comp.MoveDown)
 	comp.keyHandler.RegisterKeyReleasedCommand(sf.KeyUp, comp.StopMovement)
 	comp.keyHandler.RegisterKeyReleasedCommand(sf.KeyDown, comp.StopMovement)
-	if arg, ok := args["Speed"]; ok {
-		if speed, ok := GE.ArgAsFloat32(arg); ok {
-			comp.Speed = speed
-		} else {
-			comp.Speed = 400.0
-		}
-	}
 	return &comp
 }
 
@@ -62,12 +64,11 @@ func (comp *PaddleComponent) Awake() {
 
 //Update : Moves the Paddle
 func (comp *PaddleComponent) Update(dur time.Duration) {
-	transform := comp.GetEntity().Transfrom
 	distance := sf.Vector2f{
 		X: 0,
 		Y: comp.direction * comp.Speed * float32(dur.Seconds()),
 	}
-	transform.Move(distance)
+	comp.GetEntity().Move(distance)
 }
 
 //Sleep : Component stops listening for input
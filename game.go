@@ -1,16 +1,22 @@
 package goldengine
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"log"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 )
 
 var gRunning = true
 
+//GameStoppedMSG : Game stopped and every entity was stopped. Sends pointer to game
+const GameStoppedMSG = MessageType("GameStopped")
+
 //GlobalGame : Currently running game
 var GlobalGame *Game
 
@@ -29,6 +35,26 @@ type Game struct {
 	prefabsFolder       []os.FileInfo
 	resourcesFolder     []os.FileInfo
 	scenesFolder        []os.FileInfo
+	timeStep            time.Duration
+	maxFrameTime        time.Duration
+	accumulator         time.Duration
+	frame               uint64
+	paused              bool
+	pendingSteps        int
+	timeScale           float64
+	clockLock           sync.Mutex
+	started             bool
+	commands            []func()
+	commandLock         sync.Mutex
+	quit                chan struct{}
+	quitOnce            sync.Once
+	seed                int64
+	random              *rand.Rand
+	profiler            *Profiler
+	hotReload           bool
+	hotReloadInterval   time.Duration
+	scenePaths          map[string]string
+	systems             systemList
 	//Composed Structs
 	BasicMailBox //TODO : Use this for RPC
 }
@@ -39,6 +65,10 @@ Defaults
 
 Name: App:NoName
 LogFile: os.Stdout
+TimeStep: 1/60 of a second
+MaxFrameTime: 1/4 of a second
+Seed: current time
+HotReloadInterval: 1 second
 */
 type GameConfig struct {
 	Name                string
@@ -47,6 +77,18 @@ type GameConfig struct {
 	ResourcesFolderName string
 	ScenesFolderName    string
 	Debug               bool
+	//TimeStep : Simulated time of every fixed update
+	TimeStep time.Duration
+	//MaxFrameTime : Most real time simulated in one frame. Stops the game
+	//from falling further behind when updates are slow
+	MaxFrameTime time.Duration
+	//Seed : Seed of Game.Rand
+	Seed int64
+	//HotReload : Watch the prefabs, scenes and resources folders while running
+	//and reload files that change
+	HotReload bool
+	//HotReloadInterval : How often watched folders are polled
+	HotReloadInterval time.Duration
 }
 
 const (
@@ -56,6 +98,10 @@ const (
 	DefaultResourcesFolderName = "resources"
 	//DefaultScenesFolderName : scenes
 	DefaultScenesFolderName = "scenes"
+	//DefaultTimeStep : 60 fixed updates a second
+	DefaultTimeStep = time.Second / 60
+	//DefaultMaxFrameTime : Simulate at most a quarter second per frame
+	DefaultMaxFrameTime = time.Second / 4
 )
 
 //NewGame : Returns an app.
@@ -83,20 +129,27 @@ func NewGame(config GameConfig, wc WindowConfig, pc PhysicsEngineConfig) *Game {
 	if scenesFolderName == "" {
 		scenesFolderName = DefaultScenesFolderName
 	}
-
-	prefabsFolder, err := ioutil.ReadDir(prefabsFolderName)
-	if err != nil {
-		panic(err)
+	timeStep := config.TimeStep
+	if timeStep <= 0 {
+		timeStep = DefaultTimeStep
 	}
-	resourcesFolder, err := ioutil.ReadDir(resourcesFolderName)
-	if err != nil {
-		panic(err)
+	maxFrameTime := config.MaxFrameTime
+	if maxFrameTime <= 0 {
+		maxFrameTime = DefaultMaxFrameTime
 	}
-	scenesFolder, err := ioutil.ReadDir(scenesFolderName)
-	if err != nil {
-		panic(err)
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	hotReloadInterval := config.HotReloadInterval
+	if hotReloadInterval <= 0 {
+		hotReloadInterval = DefaultHotReloadInterval
 	}
 
+	prefabsFolder := readFolder(Logger, prefabsFolderName)
+	resourcesFolder := readFolder(Logger, resourcesFolderName)
+	scenesFolder := readFolder(Logger, scenesFolderName)
+
 	app := Game{
 		name:                name,
 		logger:              Logger,
@@ -111,6 +164,15 @@ func NewGame(config GameConfig, wc WindowConfig, pc PhysicsEngineConfig) *Game {
 		scenesFolder:        scenesFolder,
 		debug:               config.Debug,
 		scenes:              make(map[string]*Scene),
+		timeStep:            timeStep,
+		maxFrameTime:        maxFrameTime,
+		timeScale:           1,
+		quit:                make(chan struct{}),
+		seed:                seed,
+		random:              rand.New(rand.NewSource(seed)),
+		hotReload:           config.HotReload,
+		hotReloadInterval:   hotReloadInterval,
+		scenePaths:          make(map[string]string),
 	}
 	app.PostOffice.Add(app.window)
 	app.PostOffice.Add(app.physicsEngine)
@@ -120,6 +182,20 @@ func NewGame(config GameConfig, wc WindowConfig, pc PhysicsEngineConfig) *Game {
 	return &app
 }
 
+//readFolder : Files of a game folder. A missing folder is logged and has no
+//files, so a game without prefabs or scenes still starts
+func readFolder(logger *log.Logger, name string) []os.FileInfo {
+	files, err := ioutil.ReadDir(name)
+	if os.IsNotExist(err) {
+		logger.Printf("Folder %q doesn't exist, nothing is loaded from it", name)
+		return nil
+	}
+	if err != nil {
+		panic(err)
+	}
+	return files
+}
+
 //Init : Loads prefabs resources and scenes
 func (g *Game) Init() {
 	for _, prefabFile := range g.prefabsFolder {
@@ -143,12 +219,15 @@ func (g *Game) Init() {
 
 //GetSize : Get the size of the game window
 func (g *Game) GetSize() Vector {
-	return Vector2uToVector(g.GetWindow().renderWindow.GetSize())
+	return Vector2uToVector(g.GetWindow().backend.GetSize())
 }
 
 //LoadSceneFromFile : Gets Scene from File
 func (g *Game) LoadSceneFromFile(path string) (*Scene, error) {
 	dat, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
 	def, err := ParseSceneDef(g, filepath.Base(path), string(dat))
 	if err != nil {
 		return nil, err
@@ -158,7 +237,10 @@ func (g *Game) LoadSceneFromFile(path string) (*Scene, error) {
 	if err != nil {
 		return nil, err
 	}
+	scene.game = g
+	g.PostOffice.Add(scene)
 	g.scenes[scene.Name] = scene
+	g.scenePaths[path] = scene.Name
 	return scene, nil
 
 }
@@ -168,16 +250,137 @@ func (g *Game) GetWindow() *Window {
 	return g.window
 }
 
-//ChangeScene : Loads a new scne
+//ChangeScene : Replaces every scene in the scene stack with the named one.
+//Once the game has started the outgoing scenes are stopped and the new one
+//started at the beginning of the next frame
 func (g *Game) ChangeScene(name string) {
+	g.TransitionToScene(name, nil)
+}
+
+//TransitionToScene : ChangeScene that draws t between the old and new scenes
+func (g *Game) TransitionToScene(name string, t Transition) {
+	scene := g.mustGetScene(name)
+	g.runOnLoop(func() {
+		g.switchScene(scene, t)
+	})
+}
+
+//PushScene : Puts the named scene over the current ones. layer.Scene is ignored
+func (g *Game) PushScene(name string, layer SceneLayer) {
+	layer.Scene = g.mustGetScene(name)
+	g.runOnLoop(func() {
+		g.pushScene(&layer)
+	})
+}
+
+//PopScene : Stops and removes the top scene. The last scene is never popped
+func (g *Game) PopScene() {
+	g.runOnLoop(g.popScene)
+}
+
+func (g *Game) mustGetScene(name string) *Scene {
 	scene, ok := g.scenes[name]
 	if !ok {
 		panic("No Scene with that name")
 	}
+	return scene
+}
+
+func (g *Game) switchScene(scene *Scene, t Transition) {
+	from := g.window.scenes
+	if len(from) == 1 && from.top() == scene {
+		return
+	}
+	if g.started {
+		for i := len(from) - 1; i >= 0; i-- {
+			from[i].Scene.stop()
+		}
+	}
 	g.PostOffice.Broadcast(Message{
 		Message: SceneChangedMSG,
 		Content: scene,
 	})
+	g.window.startTransition(t, from)
+	g.syncScenes()
+}
+
+func (g *Game) pushScene(layer *SceneLayer) {
+	if g.window.scenes.contains(layer.Scene) {
+		g.logger.Printf("Scene %q is already in the scene stack", layer.Scene.Name)
+		return
+	}
+	from := append(sceneStack(nil), g.window.scenes...)
+	g.PostOffice.Broadcast(Message{
+		Message: ScenePushedMSG,
+		Content: layer,
+	})
+	g.window.startTransition(layer.Transition, from)
+	g.syncScenes()
+}
+
+func (g *Game) popScene() {
+	from := append(sceneStack(nil), g.window.scenes...)
+	if len(from) <= 1 {
+		return
+	}
+	layer := from[len(from)-1]
+	if g.started {
+		layer.Scene.stop()
+	}
+	g.PostOffice.Broadcast(Message{
+		Message: ScenePoppedMSG,
+		Content: layer.Scene,
+	})
+	g.window.startTransition(layer.Transition, from)
+	g.syncScenes()
+}
+
+//syncScenes : Once started, wakes the scenes that update and sleeps the ones that don't
+func (g *Game) syncScenes() {
+	if !g.started {
+		return
+	}
+	updating := g.window.scenes.updating()
+	for _, layer := range g.window.scenes {
+		if containsScene(updating, layer.Scene) {
+			layer.Scene.start()
+			layer.Scene.awake()
+		} else {
+			layer.Scene.sleep()
+		}
+	}
+}
+
+func containsScene(scenes []*Scene, s *Scene) bool {
+	for _, scene := range scenes {
+		if scene == s {
+			return true
+		}
+	}
+	return false
+}
+
+//runOnLoop : Runs cmd at the beginning of the next Advance once the game has
+//started. Runs it right away before that
+func (g *Game) runOnLoop(cmd func()) {
+	g.commandLock.Lock()
+	if g.started {
+		g.commands = append(g.commands, cmd)
+		g.commandLock.Unlock()
+		return
+	}
+	g.commandLock.Unlock()
+	cmd()
+}
+
+func (g *Game) runCommands() {
+	g.commandLock.Lock()
+	commands := g.commands
+	g.commands = nil
+	g.commandLock.Unlock()
+	for _, cmd := range commands {
+		cmd()
+	}
 }
 
 //GetScene : Returns a scene
@@ -186,9 +389,9 @@ func (g *Game) GetScene(name string) (*Scene, bool) {
 	return scene, ok
 }
 
-//GetCurrentScene : Get the current rendered scene
+//GetCurrentScene : Get the scene on top of the scene stack
 func (g *Game) GetCurrentScene() *Scene {
-	return g.window.scene
+	return g.window.GetScene()
 }
 
 //ProcessArguments : Handles CommandLine Arguments
@@ -196,22 +399,172 @@ func (g *Game) ProcessArguments() {
 	g.logger.Printf("Done")
 }
 
-//Run : Runs Game
-func (g *Game) Run() {
+//TimeStep : Simulated time of every fixed update
+func (g *Game) TimeStep() time.Duration {
+	return g.timeStep
+}
+
+//Rand : Seeded random source. Use it for gameplay so replays match
+func (g *Game) Rand() *rand.Rand {
+	return g.random
+}
+
+//Seed : Reseeds Rand
+func (g *Game) Seed(seed int64) {
+	g.seed = seed
+	g.random.Seed(seed)
+}
+
+//Frame : Number of fixed updates simulated so far
+func (g *Game) Frame() uint64 {
+	return g.frame
+}
+
+//Advance : Feeds real elapsed time into the simulation. Runs as many fixed
+//updates as fit into the accumulated time and returns the interpolation alpha
+//between the last simulated state and the next one. Elapsed time is scaled by
+//the game's time scale. While paused only frames requested with StepFrame run
+func (g *Game) Advance(elapsed time.Duration) float32 {
+	if elapsed > g.maxFrameTime {
+		elapsed = g.maxFrameTime
+	}
+	g.clockLock.Lock()
+	paused, steps, timeScale := g.paused, g.pendingSteps, g.timeScale
+	g.pendingSteps = 0
+	g.clockLock.Unlock()
+
+	g.window.frameLock.Lock()
+	defer g.window.frameLock.Unlock()
+	g.runCommands()
+	if paused {
+		for i := 0; i < steps; i++ {
+			g.step(g.timeStep)
+		}
+	} else {
+		g.accumulator += time.Duration(float64(elapsed) * timeScale)
+		for g.accumulator >= g.timeStep {
+			g.step(g.timeStep)
+			g.accumulator -= g.timeStep
+		}
+	}
+	alpha := float32(g.accumulator) / float32(g.timeStep)
+	g.physicsEngine.interpolate(alpha)
+	for _, layer := range g.window.scenes {
+		layer.Scene.interpolate(alpha)
+	}
+	frameTime := time.Duration(float64(elapsed) * timeScale)
+	if paused {
+		frameTime = 0
+	}
+	for _, scene := range g.window.scenes.updating() {
+		scene.updateSystems(StageBeforeRender, scene.scaleDuration(frameTime))
+	}
+	g.window.advanceTransition(elapsed)
+	return alpha
+}
+
+//step : One fixed update of every updating scene and its physics, the
+//bottom scene first. Each gets dt scaled by its time scale. Input queued since
+//the last step is applied first, so frames without steps keep it waiting
+func (g *Game) step(dt time.Duration) {
+	start := g.profiler.now()
+	defer g.profiler.frame(start)
+	if err := g.window.inputCollection.dispatch(g.frame); err != nil {
+		g.logger.Printf("Stopped recording input: %v", err)
+		g.StopRecording()
+	}
+	for _, scene := range g.window.scenes.updating() {
+		scaled := scene.scaleDuration(dt)
+		scene.preUpdateSystems(scaled)
+		scene.update(scaled)
+		scene.updateSystems(StageBeforePhysics, scaled)
+		g.physicsEngine.step(scene, scaled)
+		scene.updateSystems(StageAfterPhysics, scaled)
+		scene.postUpdateSystems(scaled)
+	}
+	for _, layer := range g.window.scenes {
+		layer.Scene.flushDestroyed()
+	}
+	g.frame++
+}
+
+//Start : Starts and wakes the scenes that update. Run calls this. Call it
+//before driving the game with Tick
+func (g *Game) Start() {
 	GlobalGame = g
+	g.commandLock.Lock()
+	g.started = true
+	g.commandLock.Unlock()
+	g.syncScenes()
+}
+
+//Stop : Joins the window goroutine, closes the window and sleeps and stops
+//every entity of every scene, the top scene first. Run calls this before
+//returning. Call it when driving the game with Tick
+func (g *Game) Stop() {
+	g.commandLock.Lock()
+	if !g.started {
+		g.commandLock.Unlock()
+		return
+	}
+	g.started = false
+	g.commands = nil
+	g.commandLock.Unlock()
+	g.window.Close()
+	g.StopRecording()
+	for i := len(g.window.scenes) - 1; i >= 0; i-- {
+		g.window.scenes[i].Scene.stop()
+	}
+	g.PostOffice.Broadcast(Message{
+		Message: GameStoppedMSG,
+		Content: g,
+	})
+}
+
+//Quit : Makes Run return after the current frame
+func (g *Game) Quit() {
+	g.quitOnce.Do(func() {
+		close(g.quit)
+	})
+}
+
+//Tick : Runs one whole frame on the calling goroutine. Polls window events,
+//advances the simulation by elapsed and renders. Drives a Game without Run,
+//for example with a HeadlessBackend in tests
+func (g *Game) Tick(elapsed time.Duration) {
+	g.window.PollEvents()
+	g.Advance(elapsed)
+	g.window.Render()
+}
+
+//Run : Runs Game until the window closes or Quit is called
+func (g *Game) Run() {
+	g.RunContext(context.Background())
+}
+
+//RunContext : Runs Game until the window closes, Quit is called or ctx is done
+func (g *Game) RunContext(ctx context.Context) {
 	window := g.window
-	renderWindow := g.window.renderWindow
-	g.window.scene.start()
-	g.window.scene.awake()
-	go window.Run()
-	now := time.Now()
-	for renderWindow.IsOpen() {
+	g.Start()
+	defer g.Stop()
+	window.Start()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if g.hotReload {
+		go g.watchFiles(ctx)
+	}
+	ticker := time.NewTicker(g.timeStep)
+	defer ticker.Stop()
+	last := time.Now()
+	for window.IsOpen() {
 		select {
-		case <-window.Ticker.C:
-			g.window.scene.update(time.Since(now))
-			now = time.Now()
-			//poll events
-
+		case <-ctx.Done():
+			return
+		case <-g.quit:
+			return
+		case now := <-ticker.C:
+			g.Advance(now.Sub(last))
+			last = now
 		}
 	}
 }
@@ -0,0 +1,126 @@
+package goldengine
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+//newTestGame : Headless game whose prefabs, resources and scenes folders are
+//made in a temporary directory from files, a map of paths relative to it
+func newTestGame(t *testing.T, config GameConfig, backend WindowBackend, files map[string]string) *Game {
+	t.Helper()
+	dir := t.TempDir()
+	for _, folder := range []string{DefaultPrefabsFolderName, DefaultResourcesFolderName, DefaultScenesFolderName} {
+		if err := os.MkdirAll(filepath.Join(dir, folder), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config.LogFile = ioutil.Discard
+	config.PrefabsFolderName = filepath.Join(dir, DefaultPrefabsFolderName)
+	config.ResourcesFolderName = filepath.Join(dir, DefaultResourcesFolderName)
+	config.ScenesFolderName = filepath.Join(dir, DefaultScenesFolderName)
+	if backend == nil {
+		backend = NewHeadlessBackend(800, 600)
+	}
+	game := NewGame(config, WindowConfig{Backend: backend}, PhysicsEngineConfig{})
+	game.Init()
+	return game
+}
+
+func TestAdvanceFixedTimeStep(t *testing.T) {
+	game := newTestGame(t, GameConfig{
+		TimeStep:     10 * time.Millisecond,
+		MaxFrameTime: 100 * time.Millisecond,
+	}, nil, nil)
+	game.Start()
+	defer game.Stop()
+	frames := []struct {
+		elapsed time.Duration
+		steps   uint64
+		alpha   float32
+	}{
+		{0, 0, 0},
+		{25 * time.Millisecond, 2, 0.5},
+		{5 * time.Millisecond, 1, 0},
+		{2500 * time.Microsecond, 0, 0.25},
+		{time.Second, 10, 0.25},
+	}
+	for i, frame := range frames {
+		before := game.Frame()
+		alpha := game.Advance(frame.elapsed)
+		if steps := game.Frame() - before; steps != frame.steps {
+			t.Errorf("frame %d: %v ran %d steps, want %d", i, frame.elapsed, steps, frame.steps)
+		}
+		if alpha != frame.alpha {
+			t.Errorf("frame %d: %v alpha %v, want %v", i, frame.elapsed, alpha, frame.alpha)
+		}
+	}
+}
+
+func TestTickTimeScaleAndPause(t *testing.T) {
+	game := newTestGame(t, GameConfig{TimeStep: 10 * time.Millisecond}, nil, nil)
+	game.Start()
+	defer game.Stop()
+	game.SetTimeScale(0.5)
+	game.Tick(40 * time.Millisecond)
+	if game.Frame() != 2 {
+		t.Fatalf("40ms at half speed ran %d steps, want 2", game.Frame())
+	}
+	game.SetTimeScale(1)
+	game.Pause()
+	game.Tick(40 * time.Millisecond)
+	if game.Frame() != 2 {
+		t.Fatalf("paused game ran %d steps, want 2", game.Frame())
+	}
+	game.StepFrame()
+	game.Tick(40 * time.Millisecond)
+	if game.Frame() != 3 {
+		t.Fatalf("StepFrame ran %d steps, want 3", game.Frame())
+	}
+	game.Resume()
+	game.Tick(15 * time.Millisecond)
+	if game.Frame() != 4 {
+		t.Fatalf("resumed game ran %d steps, want 4", game.Frame())
+	}
+}
+
+func TestNewGameSkipsMissingFolders(t *testing.T) {
+	dir := t.TempDir()
+	scenes := filepath.Join(dir, DefaultScenesFolderName)
+	if err := os.Mkdir(scenes, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(scenes, "main.scene"), []byte(`{"Name":"main"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var log bytes.Buffer
+	game := NewGame(GameConfig{
+		LogFile:             &log,
+		PrefabsFolderName:   filepath.Join(dir, DefaultPrefabsFolderName),
+		ResourcesFolderName: filepath.Join(dir, DefaultResourcesFolderName),
+		ScenesFolderName:    scenes,
+	}, WindowConfig{Backend: NewHeadlessBackend(800, 600)}, PhysicsEngineConfig{})
+	game.Init()
+	if _, ok := game.GetScene("main"); !ok {
+		t.Error("scene wasn't loaded because other folders are missing")
+	}
+	for _, folder := range []string{DefaultPrefabsFolderName, DefaultResourcesFolderName} {
+		if !strings.Contains(log.String(), filepath.Join(dir, folder)) {
+			t.Errorf("missing %s folder wasn't logged:\n%s", folder, log.String())
+		}
+	}
+	if strings.Contains(log.String(), fmt.Sprintf("%q doesn't exist", scenes)) {
+		t.Errorf("existing scenes folder was logged as missing:\n%s", log.String())
+	}
+}
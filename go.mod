@@ -0,0 +1,3 @@
+module github.com/Dacode45/goldengine
+
+go 1.18
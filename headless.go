@@ -0,0 +1,149 @@
+package goldengine
+
+import (
+	"sync"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//DrawCall : Something drawn onto a HeadlessBackend
+type DrawCall struct {
+	Drawer       sf.Drawer
+	RenderStates sf.RenderStates
+}
+
+//HeadlessBackend : WindowBackend without a display. Events are injected
+//instead of coming from the OS and draw calls are recorded instead of rendered.
+//Lets a Game run in tests and on servers
+type HeadlessBackend struct {
+	size       sf.Vector2u
+	open       bool
+	events     []sf.Event
+	clearColor sf.Color
+	drawCalls  []DrawCall
+	lastFrame  []DrawCall
+	frames     int
+	mu         sync.Mutex
+}
+
+//NewHeadlessBackend : Creates an open HeadlessBackend of the given size in pixels
+func NewHeadlessBackend(width, height uint) *HeadlessBackend {
+	if width <= 0 {
+		width = DefaultGameWidth
+	}
+	if height <= 0 {
+		height = DefaultGameHeight
+	}
+	return &HeadlessBackend{
+		size: sf.Vector2u{X: width, Y: height},
+		open: true,
+	}
+}
+
+//Clear : Starts recording a new frame
+func (h *HeadlessBackend) Clear(color sf.Color) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clearColor = color
+	h.drawCalls = nil
+}
+
+//Draw : Records a draw call for the current frame
+func (h *HeadlessBackend) Draw(drawer sf.Drawer, renderStates sf.RenderStates) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drawCalls = append(h.drawCalls, DrawCall{
+		Drawer:       drawer,
+		RenderStates: renderStates,
+	})
+}
+
+//Display : Finishes the current frame
+func (h *HeadlessBackend) Display() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastFrame = h.drawCalls
+	h.drawCalls = nil
+	h.frames++
+}
+
+//GetSize : Size in pixels
+func (h *HeadlessBackend) GetSize() sf.Vector2u {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.size
+}
+
+//SetSize : Resizes the backend and queues an sf.EventResized
+func (h *HeadlessBackend) SetSize(width, height uint) {
+	h.mu.Lock()
+	h.size = sf.Vector2u{X: width, Y: height}
+	h.mu.Unlock()
+	h.InjectEvent(sf.EventResized{Width: width, Height: height})
+}
+
+//IsOpen : False once Close is called
+func (h *HeadlessBackend) IsOpen() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.open
+}
+
+//Close : Closes the backend. Game.Run returns after this
+func (h *HeadlessBackend) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.open = false
+}
+
+//PollEvent : Pops the oldest injected event. nil when there are none
+func (h *HeadlessBackend) PollEvent() sf.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.events) == 0 {
+		return nil
+	}
+	event := h.events[0]
+	h.events = h.events[1:]
+	return event
+}
+
+//InjectEvent : Queues an event as if it came from the OS
+func (h *HeadlessBackend) InjectEvent(event sf.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event)
+}
+
+//PressKey : Queues an sf.EventKeyPressed
+func (h *HeadlessBackend) PressKey(code sf.KeyCode) {
+	h.InjectEvent(sf.EventKeyPressed{Code: code})
+}
+
+//ReleaseKey : Queues an sf.EventKeyReleased
+func (h *HeadlessBackend) ReleaseKey(code sf.KeyCode) {
+	h.InjectEvent(sf.EventKeyReleased{Code: code})
+}
+
+//ClearColor : Color of the last Clear
+func (h *HeadlessBackend) ClearColor() sf.Color {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.clearColor
+}
+
+//LastFrame : Draw calls of the last displayed frame, in draw order
+func (h *HeadlessBackend) LastFrame() []DrawCall {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	frame := make([]DrawCall, len(h.lastFrame))
+	copy(frame, h.lastFrame)
+	return frame
+}
+
+//FrameCount : Number of frames displayed
+func (h *HeadlessBackend) FrameCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.frames
+}
@@ -0,0 +1,219 @@
+package goldengine
+
+import (
+	"errors"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//ErrParentCycle : An entity can't become a child of itself or of its own children
+var ErrParentCycle = errors.New("An entity can't be parented to itself or its children")
+
+//worldCache : Last world matrix of an entity. It's dirty from when the entity,
+//one of its parents or its place in the hierarchy changes until the matrix is
+//computed again. A clean entity always has clean parents
+type worldCache struct {
+	valid bool
+	world Matrix
+}
+
+//LocalMatrix : Transform of the entity relative to its parent
+func (e *Entity) LocalMatrix() Matrix {
+	if e.Transfrom == nil {
+		return IdentityMatrix
+	}
+	return MatrixFromTransformer(e.Transfrom)
+}
+
+//WorldMatrix : Transform of the entity relative to the scene. Cached until
+//the entity or one of its parents moves
+func (e *Entity) WorldMatrix() Matrix {
+	if e.world.valid {
+		return e.world.world
+	}
+	e.world.world = e.parentWorldMatrix().Multiply(e.LocalMatrix())
+	e.world.valid = true
+	return e.world.world
+}
+
+//parentWorldMatrix : World matrix of the parent, the identity without one
+func (e *Entity) parentWorldMatrix() Matrix {
+	if e.parent == nil {
+		return IdentityMatrix
+	}
+	return e.parent.WorldMatrix()
+}
+
+//MarkTransformDirty : Makes the world matrix of the entity and its children
+//be computed again. SetPosition, SetRotation, SetScale, Move and SetParent
+//call it when they change something, call it after changing Transfrom directly
+func (e *Entity) MarkTransformDirty() {
+	if e.scene != nil {
+		e.scene.spatialMoved(e)
+	}
+	e.invalidateWorld()
+}
+
+//invalidateWorld : Makes the world matrices of the subtree dirty. Children of
+//a dirty entity are already dirty
+func (e *Entity) invalidateWorld() {
+	if !e.world.valid {
+		return
+	}
+	e.world.valid = false
+	for _, child := range e.children {
+		child.invalidateWorld()
+	}
+}
+
+//SetPosition : Moves the entity relative to its parent
+func (e *Entity) SetPosition(position sf.Vector2f) {
+	if e.Transfrom == nil || e.Transfrom.GetPosition() == position {
+		return
+	}
+	e.Transfrom.SetPosition(position)
+	e.MarkTransformDirty()
+}
+
+//Move : Moves the entity by offset relative to its parent
+func (e *Entity) Move(offset sf.Vector2f) {
+	if e.Transfrom == nil || offset == (sf.Vector2f{}) {
+		return
+	}
+	e.Transfrom.Move(offset)
+	e.MarkTransformDirty()
+}
+
+//SetRotation : Rotates the entity relative to its parent, in degrees
+func (e *Entity) SetRotation(rotation float32) {
+	if e.Transfrom == nil || e.Transfrom.GetRotation() == rotation {
+		return
+	}
+	e.Transfrom.SetRotation(rotation)
+	e.MarkTransformDirty()
+}
+
+//SetScale : Scales the entity relative to its parent
+func (e *Entity) SetScale(scale sf.Vector2f) {
+	if e.Transfrom == nil || e.Transfrom.GetScale() == scale {
+		return
+	}
+	e.Transfrom.SetScale(scale)
+	e.MarkTransformDirty()
+}
+
+//WorldPosition : Position of the entity in the scene
+func (e *Entity) WorldPosition() sf.Vector2f {
+	var local sf.Vector2f
+	if e.Transfrom != nil {
+		local = e.Transfrom.GetPosition()
+	}
+	return e.parentWorldMatrix().TransformPoint(local)
+}
+
+//SetWorldPosition : Moves the entity to a position in the scene
+func (e *Entity) SetWorldPosition(position sf.Vector2f) {
+	if e.Transfrom == nil {
+		return
+	}
+	e.SetPosition(e.parentWorldMatrix().Inverse().TransformPoint(position))
+}
+
+//WorldRotation : Rotation of the entity in the scene in degrees
+func (e *Entity) WorldRotation() float32 {
+	var rotation float32
+	for p := e; p != nil; p = p.parent {
+		if p.Transfrom != nil {
+			rotation += p.Transfrom.GetRotation()
+		}
+	}
+	return rotation
+}
+
+//WorldScale : Scale of the entity in the scene
+func (e *Entity) WorldScale() sf.Vector2f {
+	scale := sf.Vector2f{X: 1, Y: 1}
+	for p := e; p != nil; p = p.parent {
+		if p.Transfrom != nil {
+			s := p.Transfrom.GetScale()
+			scale.X *= s.X
+			scale.Y *= s.Y
+		}
+	}
+	return scale
+}
+
+//GetParent : Entity this entity is a child of. nil for top level entities
+func (e *Entity) GetParent() *Entity {
+	return e.parent
+}
+
+//SetParent : Makes the entity a child of parent, or a top level entity when
+//parent is nil. With keepWorld the entity stays where it is on screen,
+//otherwise its local transform is kept and it moves with the new parent
+func (e *Entity) SetParent(parent *Entity, keepWorld bool) error {
+	for p := parent; p != nil; p = p.parent {
+		if p == e {
+			return ErrParentCycle
+		}
+	}
+	if parent == e.parent {
+		return nil
+	}
+	position, rotation, scale := e.WorldPosition(), e.WorldRotation(), e.WorldScale()
+	wasActive := e.ActiveInHierarchy()
+	if e.parent != nil {
+		e.parent.RemoveChild(e)
+	}
+	if parent != nil {
+		parent.AddChild(e)
+	}
+	if e.scene != nil {
+		e.scene.reparentNode(e)
+	}
+	e.MarkTransformDirty()
+	if keepWorld && e.Transfrom != nil {
+		parentRotation, parentScale := float32(0), sf.Vector2f{X: 1, Y: 1}
+		if parent != nil {
+			parentRotation, parentScale = parent.WorldRotation(), parent.WorldScale()
+		}
+		e.SetRotation(rotation - parentRotation)
+		if parentScale.X != 0 && parentScale.Y != 0 {
+			e.SetScale(sf.Vector2f{X: scale.X / parentScale.X, Y: scale.Y / parentScale.Y})
+		}
+		e.SetWorldPosition(position)
+	}
+	e.activeChanged(wasActive)
+	return nil
+}
+
+//reparentNode : Moves the node of an entity under the node of its new parent
+func (s *Scene) reparentNode(e *Entity) {
+	node, ok := s.entityNodeMap[e.Name]
+	if !ok || node.entity != e {
+		return
+	}
+	parentNode := s.root
+	if e.parent != nil {
+		if n, ok := s.entityNodeMap[e.parent.Name]; ok && n.entity == e.parent {
+			parentNode = n
+		}
+	}
+	if node.parent == parentNode {
+		return
+	}
+	if node.parent != nil {
+		node.parent.removeChild(node)
+	}
+	parentNode.AddChild(node)
+	s.invalidateOrder()
+}
+
+//worldRenderStates : Render states that draw an entity's local transform in the scene
+func (e *Entity) worldRenderStates(renderStates sf.RenderStates) sf.RenderStates {
+	if e.parent == nil {
+		return renderStates
+	}
+	renderStates.Transform = MatrixFromSFML(renderStates.Transform).Multiply(e.parentWorldMatrix()).ToSFML()
+	return renderStates
+}
@@ -0,0 +1,62 @@
+package goldengine
+
+import (
+	"testing"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//newShapeEntity : Entity drawn as a rectangle at position
+func newShapeEntity(t *testing.T, position sf.Vector2f) *Entity {
+	t.Helper()
+	shape, err := sf.NewRectangleShape()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEntity()
+	e.Transfrom = shape
+	e.SetPosition(position)
+	return e
+}
+
+func TestWorldMatrixDirtyFlag(t *testing.T) {
+	parent := newShapeEntity(t, sf.Vector2f{X: 10, Y: 20})
+	child := newShapeEntity(t, sf.Vector2f{X: 1, Y: 2})
+	if err := child.SetParent(parent, false); err != nil {
+		t.Fatal(err)
+	}
+	position := func(e *Entity) sf.Vector2f {
+		return e.WorldMatrix().TransformPoint(sf.Vector2f{})
+	}
+	if p := position(child); p != (sf.Vector2f{X: 11, Y: 22}) {
+		t.Fatalf("child at %v, want {11 22}", p)
+	}
+
+	parent.Transfrom.SetPosition(sf.Vector2f{X: 100, Y: 100})
+	if p := position(child); p != (sf.Vector2f{X: 11, Y: 22}) {
+		t.Fatalf("clean matrix was computed again, child at %v", p)
+	}
+	parent.MarkTransformDirty()
+	if p := position(child); p != (sf.Vector2f{X: 101, Y: 102}) {
+		t.Fatalf("after MarkTransformDirty child at %v, want {101 102}", p)
+	}
+
+	parent.SetPosition(sf.Vector2f{X: 50, Y: 60})
+	if p := position(child); p != (sf.Vector2f{X: 51, Y: 62}) {
+		t.Fatalf("after SetPosition of the parent child at %v, want {51 62}", p)
+	}
+	child.Move(sf.Vector2f{X: 4, Y: 4})
+	if p := position(child); p != (sf.Vector2f{X: 55, Y: 66}) {
+		t.Fatalf("after Move child at %v, want {55 66}", p)
+	}
+	parent.SetScale(sf.Vector2f{X: 2, Y: 2})
+	if p := position(child); p != (sf.Vector2f{X: 60, Y: 72}) {
+		t.Fatalf("after SetScale of the parent child at %v, want {60 72}", p)
+	}
+	if err := child.SetParent(nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if p := position(child); p != (sf.Vector2f{X: 5, Y: 6}) {
+		t.Fatalf("after SetParent(nil) child at %v, want {5 6}", p)
+	}
+}
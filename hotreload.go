@@ -0,0 +1,273 @@
+package goldengine
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+//SceneReloadedMSG : Scene was rebuilt from its file. Sends pointer to SceneReload
+const SceneReloadedMSG = MessageType("SceneReloaded")
+
+//PrefabReloadedMSG : Prefab was registered again and its entities rebuilt. Sends the prefab name
+const PrefabReloadedMSG = MessageType("PrefabReloaded")
+
+//ResourceChangedMSG : File in the resources folder changed. Sends the path
+const ResourceChangedMSG = MessageType("ResourceChanged")
+
+//DefaultHotReloadInterval : How often watched folders are polled
+const DefaultHotReloadInterval = time.Second
+
+//SceneReload : Scene that replaces Old after its file changed
+type SceneReload struct {
+	Old *Scene
+	New *Scene
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+//fileWatcher : Polls folders for new and changed files
+type fileWatcher struct {
+	folders []string
+	stamps  map[string]fileStamp
+}
+
+//newFileWatcher : Watches folders. Files that already exist aren't reported
+func newFileWatcher(folders ...string) *fileWatcher {
+	watcher := &fileWatcher{
+		folders: folders,
+		stamps:  make(map[string]fileStamp),
+	}
+	watcher.poll()
+	return watcher
+}
+
+//poll : Files added or changed since the last poll, folder by folder
+func (watcher *fileWatcher) poll() []string {
+	var changed []string
+	for _, folder := range watcher.folders {
+		files, err := ioutil.ReadDir(folder)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			path := filepath.Join(folder, file.Name())
+			stamp := fileStamp{
+				modTime: file.ModTime(),
+				size:    file.Size(),
+			}
+			if old, ok := watcher.stamps[path]; !ok || old != stamp {
+				changed = append(changed, path)
+			}
+			watcher.stamps[path] = stamp
+		}
+	}
+	return changed
+}
+
+//watchFiles : Reloads changed prefabs, scenes and resources until ctx is done
+func (g *Game) watchFiles(ctx context.Context) {
+	watcher := newFileWatcher(g.PrefabsFolderName, g.ScenesFolderName, g.ResourcesFolderName)
+	ticker := time.NewTicker(g.hotReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, path := range watcher.poll() {
+				g.ReloadFile(path)
+			}
+		}
+	}
+}
+
+//ReloadFile : Reloads a file from the prefabs, scenes or resources folder at
+//the beginning of the next frame. Failures are logged and the old version kept
+func (g *Game) ReloadFile(path string) {
+	g.runOnLoop(func() {
+		if err := g.reloadFile(path); err != nil {
+			g.logger.Printf("Reloading %q failed: %v", path, err)
+		}
+	})
+}
+
+func (g *Game) reloadFile(path string) error {
+	switch filepath.Dir(path) {
+	case filepath.Clean(g.PrefabsFolderName):
+		return g.reloadPrefab(path)
+	case filepath.Clean(g.ScenesFolderName):
+		return g.reloadScene(path)
+	case filepath.Clean(g.ResourcesFolderName):
+		g.PostOffice.Broadcast(Message{
+			Message: ResourceChangedMSG,
+			Content: path,
+		})
+		return nil
+	}
+	return fmt.Errorf("%s isn't in a watched folder", path)
+}
+
+//reloadPrefab : Registers the prefab again and rebuilds its entities in every scene
+func (g *Game) reloadPrefab(path string) error {
+	name, err := PrefabRegister.registerFile(path)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(g.scenes))
+	for sceneName := range g.scenes {
+		names = append(names, sceneName)
+	}
+	sort.Strings(names)
+	for _, sceneName := range names {
+		scene := g.scenes[sceneName]
+		entities := scene.GetEntities()
+		sort.Slice(entities, func(i, j int) bool {
+			return entities[i].id < entities[j].id
+		})
+		for _, e := range entities {
+			if e.prefab != name {
+				continue
+			}
+			if err := scene.reinstantiate(e); err != nil {
+				return err
+			}
+		}
+	}
+	g.PostOffice.Broadcast(Message{
+		Message: PrefabReloadedMSG,
+		Content: name,
+	})
+	g.logger.Printf("Prefab %q reloaded", name)
+	return nil
+}
+
+//reloadScene : Builds the scene again from its file and swaps it into the scene stack
+func (g *Game) reloadScene(path string) error {
+	old, _ := g.scenes[g.scenePaths[path]]
+	scene, err := g.LoadSceneFromFile(path)
+	if err != nil {
+		return err
+	}
+	g.PostOffice.Broadcast(Message{
+		Message: SceneLoadedMSG,
+		Content: scene,
+	})
+	if old == nil || old == scene {
+		return nil
+	}
+	scene.Awake, scene.Start, scene.Update = old.Awake, old.Start, old.Update
+	scene.Sleep, scene.Stop = old.Sleep, old.Stop
+	scene.timeScale = old.timeScale
+	scene.systems = old.systems
+	if old.Name != scene.Name {
+		delete(g.scenes, old.Name)
+	}
+	if g.window.scenes.contains(old) {
+		if g.started {
+			old.stop()
+		}
+		g.PostOffice.Broadcast(Message{
+			Message: SceneReloadedMSG,
+			Content: &SceneReload{
+				Old: old,
+				New: scene,
+			},
+		})
+		g.syncScenes()
+	}
+	g.PostOffice.Remove(old.GetAddress())
+	g.logger.Printf("Scene %q reloaded", scene.Name)
+	return nil
+}
+
+//reinstantiate : Rebuilds an entity from its prefab keeping its name,
+//transform, place in the hierarchy, KeyboardSet, tags, layer and whether it's
+//enabled
+func (s *Scene) reinstantiate(old *Entity) error {
+	def, fromDef := s.entityDefMap[old.id]
+	if !fromDef {
+		def = SceneDefEntity{
+			Prefab: old.prefab,
+		}
+	}
+	def.Name = old.Name
+	e, err := EntityFromSceneDefEntity(def)
+	if err != nil {
+		return err
+	}
+	if old.Transfrom != nil && e.Transfrom != nil {
+		e.SetPosition(old.Transfrom.GetPosition())
+		e.SetScale(old.Transfrom.GetScale())
+		e.SetRotation(old.Transfrom.GetRotation())
+	}
+	e.KeyboardSet = old.KeyboardSet
+	e.disabled = old.disabled
+	e.layer = old.layer
+	for _, tag := range old.tags {
+		if !e.HasTag(tag) {
+			e.tags = append(e.tags, tag)
+		}
+	}
+	e.scene = s
+	if s.world != nil && old.ecs != 0 {
+		if link := GetData[EntityLink](s.world, old.ecs); link != nil {
+			link.Entity = e
+			e.ecs, e.ecsPosition = old.ecs, old.ecsPosition
+		}
+		old.ecs = 0
+	}
+	if parent := old.parent; parent != nil {
+		parent.RemoveChild(old)
+		parent.AddChild(e)
+	}
+	for _, child := range old.children {
+		e.AddChild(child)
+	}
+	old.children = nil
+
+	started, awake := old.started, old.awake
+	old.Stop()
+	if s.game != nil {
+		s.game.physicsEngine.RemoveEntity(old)
+	}
+	if node, ok := s.entityNodeMap[old.Name]; ok {
+		node.entity = e
+	}
+	s.invalidateOrder()
+	for _, tag := range old.tags {
+		s.unindexTag(old, tag)
+	}
+	for _, tag := range e.tags {
+		s.indexTag(e, tag)
+	}
+	delete(s.entityMap, old.id)
+	s.entityMap[e.id] = e
+	if s.spatial != nil {
+		s.spatial.remove(old.id)
+	}
+	s.spatialMoved(e)
+	if fromDef {
+		delete(s.entityDefMap, old.id)
+		s.entityDefMap[e.id] = def
+	}
+	if s.game != nil {
+		s.game.physicsEngine.AddEntity(e)
+	}
+	if started {
+		e.Start()
+	}
+	if awake {
+		e.Awake()
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package goldengine
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReloadPrefabKeepsEntityState(t *testing.T) {
+	game := newTestGame(t, GameConfig{}, nil, map[string]string{
+		"prefabs/crate.json": `{"Name":"crate","Tags":["crate"]}`,
+		"scenes/main.scene":  `{"Name":"main","Entities":[{"Name":"box","Prefab":"crate"}]}`,
+	})
+	game.ChangeScene("main")
+	game.Start()
+	defer game.Stop()
+	scene := game.GetCurrentScene()
+	old, _ := scene.GetEntityByName("box")
+	old.AddTag("loot")
+	old.SetLayer("pickups")
+	old.SetEnabled(false)
+	scene.FindByTag("loot")
+
+	path := filepath.Join(game.PrefabsFolderName, "crate.json")
+	if err := ioutil.WriteFile(path, []byte(`{"Name":"crate","Tags":["crate","heavy"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := game.reloadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := scene.GetEntityByName("box")
+	if e == old {
+		t.Fatal("entity wasn't rebuilt")
+	}
+	if tags := e.Tags(); !reflect.DeepEqual(tags, []string{"crate", "heavy", "loot"}) {
+		t.Errorf("tags %v, want [crate heavy loot]", tags)
+	}
+	if found := scene.FindByTag("loot"); len(found) != 1 || found[0] != e {
+		t.Errorf("FindByTag found %v, want the rebuilt entity", found)
+	}
+	if e.Layer() != "pickups" {
+		t.Errorf("layer %q, want pickups", e.Layer())
+	}
+	if e.IsEnabled() || e.awake {
+		t.Errorf("disabled entity came back enabled %v or awake %v", e.IsEnabled(), e.awake)
+	}
+}
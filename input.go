@@ -1,6 +1,7 @@
 package goldengine
 
 import (
+	"sync"
 	"sync/atomic"
 
 	sf "github.com/manyminds/gosfml"
@@ -18,21 +19,74 @@ var keyboardSetCounter uint32 = 1
 //Used to emulate keyboard input.
 type InputCollection struct {
 	keyboardSets map[uint32]*KeyboardSet
+	pending      []InputEvent
+	recorder     *InputRecorder
+	replay       *InputReplay
+	mu           sync.Mutex
 	BasicMailBox
 }
 
-//RecieveMessage : Fuffils the MailBox Requirement
+//RecieveMessage : Fuffils the MailBox Requirement. Input is queued until the
+//game loop dispatches it
 func (collection *InputCollection) RecieveMessage(msg Message) {
 	switch msg.Message {
-	case KeyPressedMSG:
+	case KeyPressedMSG, KeyReleasedMSG:
 		code := msg.Content.(*sf.KeyCode)
-		collection.KeyPressed(*code)
+		collection.mu.Lock()
+		collection.pending = append(collection.pending, InputEvent{
+			Message: msg.Message,
+			Code:    *code,
+		})
+		collection.mu.Unlock()
+	}
+}
+
+//dispatch : Applies queued input on the game loop, stamped with frame.
+//While replaying, recorded input replaces live input. Errors if the recorder
+//failed, input is still applied
+func (collection *InputCollection) dispatch(frame uint64) error {
+	collection.mu.Lock()
+	events := collection.pending
+	collection.pending = nil
+	recorder, replay := collection.recorder, collection.replay
+	collection.mu.Unlock()
+	if replay != nil {
+		events = replay.due(frame)
+	}
+	var err error
+	for _, ev := range events {
+		ev.Frame = frame
+		if recorder != nil && err == nil {
+			err = recorder.Record(ev)
+		}
+		collection.apply(ev)
+	}
+	return err
+}
+
+func (collection *InputCollection) apply(ev InputEvent) {
+	switch ev.Message {
+	case KeyPressedMSG:
+		collection.KeyPressed(ev.Code)
 	case KeyReleasedMSG:
-		code := msg.Content.(*sf.KeyCode)
-		collection.KeyReleased(*code)
+		collection.KeyReleased(ev.Code)
 	}
 }
 
+//SetRecorder : Every dispatched input is also recorded. nil stops recording
+func (collection *InputCollection) SetRecorder(recorder *InputRecorder) {
+	collection.mu.Lock()
+	defer collection.mu.Unlock()
+	collection.recorder = recorder
+}
+
+//SetReplay : Dispatches recorded input instead of live input. nil stops replaying
+func (collection *InputCollection) SetReplay(replay *InputReplay) {
+	collection.mu.Lock()
+	defer collection.mu.Unlock()
+	collection.replay = replay
+}
+
 //KeyPressed : Puts KeyPressed
 func (collection *InputCollection) KeyPressed(code sf.KeyCode) {
 	for _, set := range collection.keyboardSets {
@@ -95,20 +149,20 @@ type KeyboardSet struct {
 	handlers map[uint32]*KeyboardHandler
 }
 
-//KeyPressed : Says a key should be pressed
+//KeyPressed : Says a key should be pressed. Commands run on the calling goroutine
 func (set *KeyboardSet) KeyPressed(code sf.KeyCode) {
 	for _, handler := range set.handlers {
 		if cmd, ok := handler.KeyPressedCommands[code]; ok {
-			go cmd()
+			cmd()
 		}
 	}
 }
 
-//KeyReleased : Says a key should be released
+//KeyReleased : Says a key should be released. Commands run on the calling goroutine
 func (set *KeyboardSet) KeyReleased(code sf.KeyCode) {
 	for _, handler := range set.handlers {
 		if cmd, ok := handler.KeyReleasedCommands[code]; ok {
-			go cmd()
+			cmd()
 		}
 	}
 }
@@ -0,0 +1,253 @@
+package goldengine
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/vova616/chipmunk"
+)
+
+//DefaultLayer : Layer of entities that don't name one
+const DefaultLayer = "Default"
+
+//maxCollisionBits : Bits of a chipmunk layer mask the engine hands out
+const maxCollisionBits = 32
+
+//layerRegister : Named layers. A layer decides where an entity is drawn
+//relative to other layers and which layers its collider touches
+type layerRegister struct {
+	names     []string
+	index     map[string]int
+	drawOrder map[string]int
+	//noCollide holds pairs of layer indexes, lowest first, that pass through each other
+	noCollide map[[2]int]bool
+	masks     []chipmunk.Layer
+	//Bumped when masks change so colliders get the new ones
+	version uint64
+	//lock guards the register, since components updating in parallel set layers
+	lock sync.Mutex
+}
+
+//LayerRegister : Every layer entities can be on
+var LayerRegister = newLayerRegister()
+
+func newLayerRegister() *layerRegister {
+	register := &layerRegister{
+		index:     make(map[string]int),
+		drawOrder: make(map[string]int),
+		noCollide: make(map[[2]int]bool),
+	}
+	register.Define(DefaultLayer, 0)
+	return register
+}
+
+//Define : Adds a layer or changes its draw order. Layers with a lower draw
+//order are drawn first, below the others, whatever their z index. New layers
+//collide with every layer
+func (register *layerRegister) Define(name string, drawOrder int) {
+	register.lock.Lock()
+	defer register.lock.Unlock()
+	register.define(name, drawOrder)
+}
+
+func (register *layerRegister) define(name string, drawOrder int) {
+	register.drawOrder[name] = drawOrder
+	if _, ok := register.index[name]; ok {
+		return
+	}
+	register.index[name] = len(register.names)
+	register.names = append(register.names, name)
+	if err := register.computeMasks(); err != nil {
+		panic(err)
+	}
+}
+
+//ensure : Defines a layer with draw order 0 if it doesn't exist
+func (register *layerRegister) ensure(name string) {
+	register.lock.Lock()
+	defer register.lock.Unlock()
+	register.defined(name)
+}
+
+//defined : ensure for callers holding the lock
+func (register *layerRegister) defined(name string) {
+	if _, ok := register.index[name]; !ok {
+		register.define(name, 0)
+	}
+}
+
+//Layers : Names of every layer in the order they were defined
+func (register *layerRegister) Layers() []string {
+	register.lock.Lock()
+	defer register.lock.Unlock()
+	return append([]string(nil), register.names...)
+}
+
+//DrawOrder : Draw order of a layer, 0 if it isn't defined
+func (register *layerRegister) DrawOrder(name string) int {
+	if name == "" {
+		name = DefaultLayer
+	}
+	register.lock.Lock()
+	defer register.lock.Unlock()
+	return register.drawOrder[name]
+}
+
+//SetCollision : Whether colliders on the two layers touch. Both layers are
+//defined if they don't exist. Errors when the collision rules need more
+//chipmunk layer bits than there are
+func (register *layerRegister) SetCollision(a, b string, collide bool) error {
+	register.lock.Lock()
+	defer register.lock.Unlock()
+	register.defined(a)
+	register.defined(b)
+	pair := register.pair(a, b)
+	if register.noCollide[pair] == !collide {
+		return nil
+	}
+	register.noCollide[pair] = !collide
+	if err := register.computeMasks(); err != nil {
+		register.noCollide[pair] = collide
+		return err
+	}
+	return nil
+}
+
+//Collides : Colliders on the two layers touch
+func (register *layerRegister) Collides(a, b string) bool {
+	register.lock.Lock()
+	defer register.lock.Unlock()
+	register.defined(a)
+	register.defined(b)
+	return !register.noCollide[register.pair(a, b)]
+}
+
+//Mask : chipmunk layer mask of shapes on a layer
+func (register *layerRegister) Mask(name string) chipmunk.Layer {
+	mask, _, _ := register.collision(name)
+	return mask
+}
+
+func (register *layerRegister) pair(a, b string) [2]int {
+	i, j := register.index[a], register.index[b]
+	if i > j {
+		i, j = j, i
+	}
+	return [2]int{i, j}
+}
+
+//computeMasks : Gives every group of layers that all collide with each other a
+//bit, and every layer the bits of the groups it's in. Two chipmunk shapes
+//collide when their masks share a bit, so that's exactly when their layers
+//collide. Layers that don't collide with themselves also get a chipmunk group
+//of their own, since shapes in the same group never collide
+func (register *layerRegister) computeMasks() error {
+	n := len(register.names)
+	collides := func(i, j int) bool {
+		if i > j {
+			i, j = j, i
+		}
+		return !register.noCollide[[2]int{i, j}]
+	}
+	covered := make(map[[2]int]bool)
+	masks := make([]chipmunk.Layer, n)
+	bit := 0
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			if !collides(i, j) || covered[[2]int{i, j}] {
+				continue
+			}
+			if bit == maxCollisionBits {
+				return fmt.Errorf("Layer collision rules need more than %d groups of layers", maxCollisionBits)
+			}
+			//Grow a group around the pair with every layer that collides with all of it
+			group := []int{i}
+			if j != i {
+				group = append(group, j)
+			}
+			for k := 0; k < n; k++ {
+				if k == i || k == j {
+					continue
+				}
+				fits := true
+				for _, member := range group {
+					if !collides(k, member) {
+						fits = false
+						break
+					}
+				}
+				if fits {
+					group = append(group, k)
+				}
+			}
+			sort.Ints(group)
+			for x, a := range group {
+				masks[a] |= 1 << uint(bit)
+				for _, b := range group[x:] {
+					covered[[2]int{a, b}] = true
+				}
+			}
+			bit++
+		}
+	}
+	register.masks = masks
+	register.version++
+	return nil
+}
+
+//collision : chipmunk layer mask and group of shapes on a layer, and the
+//version of the masks. The group is 0, which collides with itself, unless the
+//layer doesn't collide with itself
+func (register *layerRegister) collision(name string) (chipmunk.Layer, chipmunk.Group, uint64) {
+	if name == "" {
+		name = DefaultLayer
+	}
+	register.lock.Lock()
+	defer register.lock.Unlock()
+	register.defined(name)
+	i := register.index[name]
+	group := chipmunk.Group(0)
+	if register.noCollide[[2]int{i, i}] {
+		group = chipmunk.Group(i + 1)
+	}
+	return register.masks[i], group, register.version
+}
+
+//currentVersion : Version of the masks, see version
+func (register *layerRegister) currentVersion() uint64 {
+	register.lock.Lock()
+	defer register.lock.Unlock()
+	return register.version
+}
+
+//applyCollisionLayer : Gives the shapes of the entity's collider the mask and group of its layer
+func (e *Entity) applyCollisionLayer() {
+	if e.Collider == nil {
+		return
+	}
+	mask, group, version := LayerRegister.collision(e.layer)
+	for _, shape := range e.Collider.Shapes {
+		shape.Layer = mask
+		shape.Group = group
+	}
+	e.layerVersion = version
+}
+
+//Layer : Layer of the entity
+func (e *Entity) Layer() string {
+	if e.layer == "" {
+		return DefaultLayer
+	}
+	return e.layer
+}
+
+//SetLayer : Moves the entity to a layer, defining it if it doesn't exist
+func (e *Entity) SetLayer(name string) {
+	if name == "" {
+		name = DefaultLayer
+	}
+	LayerRegister.ensure(name)
+	e.layer = name
+	e.applyCollisionLayer()
+}
@@ -0,0 +1,119 @@
+package goldengine
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+//chipmunkCollides : Whether chipmunk lets shapes on the two layers collide. Shapes
+//collide when their masks share a bit, unless they're in the same nonzero group
+func chipmunkCollides(register *layerRegister, a, b string) bool {
+	maskA, groupA, _ := register.collision(a)
+	maskB, groupB, _ := register.collision(b)
+	if groupA != 0 && groupA == groupB {
+		return false
+	}
+	return maskA&maskB != 0
+}
+
+//checkMasks : Every pair of layers collides in chipmunk exactly when Collides says so
+func checkMasks(t *testing.T, register *layerRegister) {
+	t.Helper()
+	layers := register.Layers()
+	for _, a := range layers {
+		for _, b := range layers {
+			if want, got := register.Collides(a, b), chipmunkCollides(register, a, b); got != want {
+				t.Errorf("%s and %s collide %v in chipmunk, want %v", a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestLayerMasks(t *testing.T) {
+	register := newLayerRegister()
+	for _, name := range []string{"player", "enemy", "bullet", "ghost", "wall"} {
+		register.Define(name, 0)
+	}
+	rules := []struct {
+		a, b string
+	}{
+		{"player", "bullet"},
+		{"bullet", "bullet"},
+		{"ghost", "player"},
+		{"ghost", "enemy"},
+		{"ghost", "ghost"},
+	}
+	for _, rule := range rules {
+		if err := register.SetCollision(rule.a, rule.b, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkMasks(t, register)
+	if register.Collides("bullet", "bullet") {
+		t.Fatal("bullets collide with each other")
+	}
+
+	//Layers defined after the masks collide with everything
+	version := register.currentVersion()
+	register.Define("pickup", 1)
+	if register.currentVersion() == version {
+		t.Error("defining a layer didn't change the masks version")
+	}
+	checkMasks(t, register)
+	for _, layer := range register.Layers() {
+		if !register.Collides("pickup", layer) {
+			t.Errorf("new layer doesn't collide with %s", layer)
+		}
+	}
+	if err := register.SetCollision("pickup", "wall", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := register.SetCollision("bullet", "bullet", true); err != nil {
+		t.Fatal(err)
+	}
+	checkMasks(t, register)
+}
+
+func TestLayerMasksRandomRules(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		register := newLayerRegister()
+		n := 2 + random.Intn(7)
+		for i := 0; i < n; i++ {
+			register.Define(fmt.Sprintf("layer%d", i), 0)
+		}
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				if random.Intn(3) == 0 {
+					a, b := fmt.Sprintf("layer%d", i), fmt.Sprintf("layer%d", j)
+					if err := register.SetCollision(a, b, false); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+		}
+		checkMasks(t, register)
+	}
+}
+
+func TestLayerMasksOutOfBits(t *testing.T) {
+	register := newLayerRegister()
+	//Layers that collide only with themselves need a bit each
+	var err error
+	for i := 0; i <= maxCollisionBits && err == nil; i++ {
+		name := fmt.Sprintf("layer%d", i)
+		register.Define(name, 0)
+		for _, other := range register.Layers() {
+			if other != name {
+				if err = register.SetCollision(name, other, false); err != nil {
+					break
+				}
+			}
+		}
+	}
+	if err == nil {
+		t.Fatal("collision rules needing more bits than chipmunk has were accepted")
+	}
+	checkMasks(t, register)
+}
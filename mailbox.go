@@ -26,6 +26,7 @@ type PostOffice struct {
 	penpals   map[Address]map[MessageType]map[Address]struct{}
 	mailboxes map[Address]MailBox
 	logger    *log.Logger
+	profiler  *Profiler
 	mu        sync.RWMutex
 }
 
@@ -34,6 +35,8 @@ func (office *PostOffice) Broadcast(msg Message) {
 	if office.logger != nil {
 		office.logger.Printf("Office %p Broadcasting Message: %s\n", office, msg.Message)
 	}
+	start := office.profiler.now()
+	defer office.profiler.end("message", string(msg.Message), start, profileMessageThread)
 	for _, box := range office.mailboxes {
 		box.RecieveMessage(msg)
 	}
@@ -45,6 +48,8 @@ func (office *PostOffice) RecieveMessage(sender Address, msg Message) {
 	if office.logger != nil {
 		office.logger.Printf("Office %p Recieved Message: %s from Address: %v\n", office, msg.Message, sender)
 	}
+	start := office.profiler.now()
+	defer office.profiler.end("message", string(msg.Message), start, profileMessageThread)
 	boxes := office.getSubscribers(sender, msg.Message)
 	for address := range boxes {
 		box, ok := office.mailboxes[address]
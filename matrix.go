@@ -0,0 +1,84 @@
+package goldengine
+
+import (
+	"math"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//Matrix : 2D affine transform. Maps (x, y) to (A*x + B*y + C, D*x + E*y + F)
+type Matrix struct {
+	A, B, C float32
+	D, E, F float32
+}
+
+//IdentityMatrix : Matrix that doesn't move anything
+var IdentityMatrix = Matrix{A: 1, E: 1}
+
+//MatrixFromTransformer : Matrix of an sfml Transformer's origin, scale, rotation and position.
+//Same as sfml's GetTransform but computed in Go
+func MatrixFromTransformer(t sf.Transformer) Matrix {
+	origin := t.GetOrigin()
+	scale := t.GetScale()
+	position := t.GetPosition()
+	angle := -float64(t.GetRotation()) * math.Pi / 180
+	cos := float32(math.Cos(angle))
+	sin := float32(math.Sin(angle))
+	sxc := scale.X * cos
+	syc := scale.Y * cos
+	sxs := scale.X * sin
+	sys := scale.Y * sin
+	return Matrix{
+		A: sxc, B: sys, C: -origin.X*sxc - origin.Y*sys + position.X,
+		D: -sxs, E: syc, F: origin.X*sxs - origin.Y*syc + position.Y,
+	}
+}
+
+//MatrixFromSFML : Converts an sfml Transform. A zero Transform is treated as the identity
+func MatrixFromSFML(t sf.Transform) Matrix {
+	m := t.Matrix
+	if m == [9]float32{} {
+		return IdentityMatrix
+	}
+	return Matrix{
+		A: m[0], B: m[1], C: m[2],
+		D: m[3], E: m[4], F: m[5],
+	}
+}
+
+//ToSFML : Converts a Matrix to an sfml Transform
+func (m Matrix) ToSFML() sf.Transform {
+	return sf.Transform{Matrix: [9]float32{
+		m.A, m.B, m.C,
+		m.D, m.E, m.F,
+		0, 0, 1,
+	}}
+}
+
+//Multiply : Matrix that applies o and then m
+func (m Matrix) Multiply(o Matrix) Matrix {
+	return Matrix{
+		A: m.A*o.A + m.B*o.D, B: m.A*o.B + m.B*o.E, C: m.A*o.C + m.B*o.F + m.C,
+		D: m.D*o.A + m.E*o.D, E: m.D*o.B + m.E*o.E, F: m.D*o.C + m.E*o.F + m.F,
+	}
+}
+
+//Inverse : Matrix that undoes m. The identity if m can't be inverted
+func (m Matrix) Inverse() Matrix {
+	det := m.A*m.E - m.B*m.D
+	if det == 0 {
+		return IdentityMatrix
+	}
+	return Matrix{
+		A: m.E / det, B: -m.B / det, C: (m.B*m.F - m.E*m.C) / det,
+		D: -m.D / det, E: m.A / det, F: (m.D*m.C - m.A*m.F) / det,
+	}
+}
+
+//TransformPoint : Applies the matrix to a point
+func (m Matrix) TransformPoint(p sf.Vector2f) sf.Vector2f {
+	return sf.Vector2f{
+		X: m.A*p.X + m.B*p.Y + m.C,
+		Y: m.D*p.X + m.E*p.Y + m.F,
+	}
+}
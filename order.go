@@ -0,0 +1,83 @@
+package goldengine
+
+import "sort"
+
+//Prioritizer : Optional Component and System method. Overrides the priority
+//the component was registered with. Systems without one have priority 0
+type Prioritizer interface {
+	UpdatePriority() int
+}
+
+//componentSlot : Component of an entity in a scene's update order
+type componentSlot struct {
+	entity    *Entity
+	component Component
+	priority  int
+	//order : Position in the update order, set for parallel updates
+	order int
+}
+
+//componentPriority : Priority of a component, 0 when nothing sets one
+func componentPriority(name string, comp Component) int {
+	if p, ok := comp.(Prioritizer); ok {
+		return p.UpdatePriority()
+	}
+	return ComponentRegister.Priority(name)
+}
+
+//nodeOrder : Nodes of the scene in tree order, parents before their
+//children and siblings in the order they were added. The root is left out
+func (s *Scene) nodeOrder() []*entityNode {
+	s.refreshOrder()
+	return s.nodes
+}
+
+//updateOrder : Components of the scene in the order they update
+func (s *Scene) updateOrder() []componentSlot {
+	s.refreshOrder()
+	return s.componentList
+}
+
+//invalidateOrder : Rebuilds the tree and update order when they're next needed
+func (s *Scene) invalidateOrder() {
+	s.orderLock.Lock()
+	s.orderValid = false
+	s.orderLock.Unlock()
+}
+
+func (s *Scene) refreshOrder() {
+	s.orderLock.Lock()
+	valid := s.orderValid && s.orderVersion == ComponentRegister.version
+	s.orderLock.Unlock()
+	if valid {
+		return
+	}
+	s.nodes = s.nodes[:0]
+	var walk func(node *entityNode)
+	walk = func(node *entityNode) {
+		for _, child := range node.children {
+			s.nodes = append(s.nodes, child)
+			walk(child)
+		}
+	}
+	walk(s.root)
+	s.componentList = s.componentList[:0]
+	for _, node := range s.nodes {
+		e := node.entity
+		for i, c := range e.components {
+			s.componentList = append(s.componentList, componentSlot{
+				entity:    e,
+				component: c,
+				priority:  componentPriority(e.componentNames[i], c),
+			})
+		}
+	}
+	sort.SliceStable(s.componentList, func(i, j int) bool {
+		return s.componentList[i].priority < s.componentList[j].priority
+	})
+	s.orderLock.Lock()
+	s.orderValid = true
+	s.orderLock.Unlock()
+	s.orderVersion = ComponentRegister.version
+	s.orderBuilds++
+}
@@ -0,0 +1,350 @@
+package goldengine
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//Access : Shared data a component reads and writes during Update, by name,
+//like "score" or "spawner". Declaring an Access, even an empty one, promises
+//that Update only touches the component's own entity tree, its top level
+//entity and every child of it, besides the named data. On its tree Update may
+//Destroy entities, add and remove tags and components, set layers and schedule
+//timers with After, Every and Sequence. It may not add, reparent, enable or
+//disable entities, post messages, register components, define layers with
+//Define or SetCollision, or find entities of other trees by tag, query or region.
+//Layers that parallel components define by setting them may be defined in any order
+type Access struct {
+	Reads  []string
+	Writes []string
+}
+
+//AccessDeclarer : Optional Component method. Components that declare what
+//they access can update in parallel in scenes with more than one update
+//worker. It's called when the update order is built, not every update
+type AccessDeclarer interface {
+	UpdateAccess() Access
+}
+
+//updateUnit : Components of one entity tree that update one after the other,
+//in update order, and the shared data they access together
+type updateUnit struct {
+	root   *Entity
+	slots  []componentSlot
+	reads  map[string]bool
+	writes map[string]bool
+}
+
+//conflicts : Running u and o at the same time could race. One writes data the other uses
+func (u *updateUnit) conflicts(o *updateUnit) bool {
+	for name := range u.writes {
+		if o.reads[name] || o.writes[name] {
+			return true
+		}
+	}
+	for name := range o.writes {
+		if u.reads[name] {
+			return true
+		}
+	}
+	return false
+}
+
+//updateStep : A component that updates alone, or waves of units. Units of a
+//wave update in parallel, waves one after the other
+type updateStep struct {
+	slot  componentSlot
+	waves [][]*updateUnit
+}
+
+//updatePlan : Steps of a scene's update and the update order they were made from
+type updatePlan struct {
+	steps  []updateStep
+	builds uint64
+	valid  bool
+}
+
+//SetUpdateWorkers : Goroutines updating the components of the scene that
+//declare an Access. 1 or less updates every component on the game loop, the
+//default. Components that don't declare an Access always update alone, in order
+func (s *Scene) SetUpdateWorkers(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers != s.updateWorkers {
+		s.stopUpdatePool()
+	}
+	s.updateWorkers = workers
+}
+
+//UpdateWorkers : Goroutines updating the scene's components. See SetUpdateWorkers
+func (s *Scene) UpdateWorkers() int {
+	if s.updateWorkers < 1 {
+		return 1
+	}
+	return s.updateWorkers
+}
+
+//DefaultUpdateWorkers : One update worker per CPU Go uses
+func DefaultUpdateWorkers() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+//updatePlan : Steps of the update, built again when the update order changes
+func (s *Scene) updatePlan() []updateStep {
+	order := s.updateOrder()
+	plan := &s.plan
+	if plan.valid && plan.builds == s.orderBuilds {
+		return plan.steps
+	}
+	plan.steps = plan.steps[:0]
+	//A run of components that declared an Access never spans two priorities,
+	//so a tree's components don't update before lower priority ones of others
+	var run []componentSlot
+	for i, slot := range order {
+		slot.order = i
+		if len(run) > 0 && run[0].priority != slot.priority {
+			plan.steps = append(plan.steps, updateStep{waves: planWaves(run)})
+			run = nil
+		}
+		if _, ok := slot.component.(AccessDeclarer); ok {
+			run = append(run, slot)
+			continue
+		}
+		if len(run) > 0 {
+			plan.steps = append(plan.steps, updateStep{waves: planWaves(run)})
+			run = nil
+		}
+		plan.steps = append(plan.steps, updateStep{slot: slot})
+	}
+	if len(run) > 0 {
+		plan.steps = append(plan.steps, updateStep{waves: planWaves(run)})
+	}
+	plan.builds = s.orderBuilds
+	plan.valid = true
+	return plan.steps
+}
+
+//planWaves : Groups components of one priority that declared an Access by
+//entity tree and puts every tree in the wave after the last earlier tree it
+//conflicts with, so conflicting trees update in the same order as they would alone
+func planWaves(slots []componentSlot) [][]*updateUnit {
+	var units []*updateUnit
+	byRoot := make(map[*Entity]*updateUnit)
+	for _, slot := range slots {
+		root := slot.entity
+		for root.parent != nil {
+			root = root.parent
+		}
+		unit, ok := byRoot[root]
+		if !ok {
+			unit = &updateUnit{
+				root:   root,
+				reads:  make(map[string]bool),
+				writes: make(map[string]bool),
+			}
+			byRoot[root] = unit
+			units = append(units, unit)
+		}
+		unit.slots = append(unit.slots, slot)
+		access := slot.component.(AccessDeclarer).UpdateAccess()
+		for _, name := range access.Reads {
+			unit.reads[name] = true
+		}
+		for _, name := range access.Writes {
+			unit.writes[name] = true
+		}
+	}
+	var waves [][]*updateUnit
+	wave := make([]int, len(units))
+	for i, unit := range units {
+		for j := 0; j < i; j++ {
+			if wave[j] >= wave[i] && unit.conflicts(units[j]) {
+				wave[i] = wave[j] + 1
+			}
+		}
+		if wave[i] == len(waves) {
+			waves = append(waves, nil)
+		}
+		waves[wave[i]] = append(waves[wave[i]], unit)
+	}
+	return waves
+}
+
+//updateSlot : Updates a component unless its entity is asleep, destroyed or lost it
+func (s *Scene) updateSlot(slot componentSlot, dur time.Duration, profiler *Profiler) {
+	e := slot.entity
+	if !e.awake || e.destroyed || !e.hasComponent(slot.component) {
+		return
+	}
+	start := profiler.now()
+	slot.component.Update(dur)
+	profiler.component(slot.component, start)
+}
+
+//updateSlots : Updates components in order and records an entity event for
+//every run of components of one entity. A root remembers which one is
+//updating so entities it destroys can be put back in update order
+func (s *Scene) updateSlots(slots []componentSlot, dur time.Duration, profiler *Profiler, root *Entity) {
+	for len(slots) > 0 {
+		e := slots[0].entity
+		run := 1
+		for run < len(slots) && slots[run].entity == e {
+			run++
+		}
+		updating := e.awake && !e.destroyed
+		start := profiler.now()
+		for _, slot := range slots[:run] {
+			if root != nil {
+				root.updateSlot = slot.order
+			}
+			s.updateSlot(slot, dur, profiler)
+		}
+		if updating {
+			profiler.end("entity", e.Name, start, profileLoopThread)
+		}
+		slots = slots[run:]
+	}
+}
+
+//update : Updates the unit's components in order
+func (u *updateUnit) update(dur time.Duration, profiler *Profiler) {
+	u.root.scene.updateSlots(u.slots, dur, profiler, u.root)
+}
+
+//updateParallel : Updates the scene's components following its update plan.
+//Entities destroyed and timers scheduled by parallel components are removed
+//and run in the order they would be if every component updated alone,
+//whichever worker got there first
+func (s *Scene) updateParallel(dur time.Duration, profiler *Profiler) {
+	for _, step := range s.updatePlan() {
+		if step.waves == nil {
+			s.updateSlots([]componentSlot{step.slot}, dur, profiler, nil)
+			continue
+		}
+		destroyed := len(s.destroyed)
+		added := len(s.scheduler.timers)
+		for _, wave := range step.waves {
+			s.updateWave(wave, dur, profiler)
+		}
+		//Workers append to destroyed under destroyLock and to the timers under
+		//the scheduler's lock. Every worker is done once the waves return, so
+		//the new entries can be sorted without them
+		tail := s.destroyed[destroyed:]
+		sort.SliceStable(tail, func(i, j int) bool {
+			return tail[i].updateSlot < tail[j].updateSlot
+		})
+		s.scheduler.sortAdded(added)
+	}
+}
+
+//waveJob : Units of a wave that workers take a chunk at a time
+type waveJob struct {
+	units    []*updateUnit
+	dur      time.Duration
+	profiler *Profiler
+	chunk    int
+	next     int64
+	panics   []interface{}
+	wg       sync.WaitGroup
+}
+
+//run : Updates chunks of units until none are left
+func (j *waveJob) run() {
+	defer j.wg.Done()
+	for {
+		end := int(atomic.AddInt64(&j.next, int64(j.chunk)))
+		start := end - j.chunk
+		if start >= len(j.units) {
+			return
+		}
+		if end > len(j.units) {
+			end = len(j.units)
+		}
+		for i := start; i < end; i++ {
+			j.update(i)
+		}
+	}
+}
+
+//update : Updates a unit, keeping its panic for the game loop
+func (j *waveJob) update(i int) {
+	defer func() {
+		j.panics[i] = recover()
+	}()
+	j.units[i].update(j.dur, j.profiler)
+}
+
+//updatePool : Goroutines that help the game loop update waves. They live until
+//the scene stops or its number of workers changes
+type updatePool struct {
+	jobs chan *waveJob
+	size int
+}
+
+func newUpdatePool(size int) *updatePool {
+	pool := &updatePool{
+		jobs: make(chan *waveJob),
+		size: size,
+	}
+	for i := 0; i < size; i++ {
+		go pool.work()
+	}
+	return pool
+}
+
+func (pool *updatePool) work() {
+	for job := range pool.jobs {
+		job.run()
+	}
+}
+
+//stopUpdatePool : Ends the goroutines of the scene's update pool
+func (s *Scene) stopUpdatePool() {
+	if s.pool != nil {
+		close(s.pool.jobs)
+		s.pool = nil
+	}
+}
+
+//updateWave : Updates the units of a wave on the game loop and the scene's
+//update pool. A panic is raised again on the game loop once the wave is done
+func (s *Scene) updateWave(units []*updateUnit, dur time.Duration, profiler *Profiler) {
+	if len(units) == 1 {
+		units[0].update(dur, profiler)
+		return
+	}
+	workers := s.UpdateWorkers()
+	if workers > len(units) {
+		workers = len(units)
+	}
+	if s.pool == nil {
+		s.pool = newUpdatePool(s.UpdateWorkers() - 1)
+	}
+	//Workers take a few units at a time so small units don't fight over next
+	chunk := len(units) / (workers * 4)
+	if chunk < 1 {
+		chunk = 1
+	}
+	job := &waveJob{
+		units:    units,
+		dur:      dur,
+		profiler: profiler,
+		chunk:    chunk,
+		panics:   make([]interface{}, len(units)),
+	}
+	job.wg.Add(workers)
+	for w := 1; w < workers; w++ {
+		s.pool.jobs <- job
+	}
+	job.run()
+	job.wg.Wait()
+	for _, p := range job.panics {
+		if p != nil {
+			panic(p)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package goldengine
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+//mover : Moves its entity tree's own state and destroys its entity on its second update
+type mover struct {
+	BaseComponent
+	x       float64
+	updates int
+	destroy bool
+}
+
+func (m *mover) UpdateAccess() Access {
+	return Access{}
+}
+
+func (m *mover) Update(dur time.Duration) {
+	m.x += dur.Seconds()
+	m.updates++
+	if m.destroy && m.updates == 2 {
+		m.GetEntity().Destroy()
+	}
+}
+
+//scorer : Writes a score whose value depends on the order scorers update in
+type scorer struct {
+	BaseComponent
+	score *int
+	add   int
+}
+
+func (s *scorer) UpdateAccess() Access {
+	return Access{Writes: []string{"score"}}
+}
+
+func (s *scorer) Update(dur time.Duration) {
+	*s.score = *s.score*3 + s.add
+}
+
+//logger : Appends to a shared log at its priority
+type logger struct {
+	BaseComponent
+	log      *[]int
+	id       int
+	priority int
+}
+
+func (l *logger) UpdateAccess() Access {
+	return Access{Writes: []string{"log"}}
+}
+
+func (l *logger) UpdatePriority() int {
+	return l.priority
+}
+
+func (l *logger) Update(dur time.Duration) {
+	*l.log = append(*l.log, l.id)
+}
+
+//counter : Updates alone since it declares no Access
+type counter struct {
+	BaseComponent
+	count *int
+}
+
+func (c *counter) Update(dur time.Duration) {
+	*c.count++
+}
+
+//parallelRun : What a scene of many entity trees did over a few updates
+type parallelRun struct {
+	score     int
+	count     int
+	log       []int
+	destroyed []int
+	moved     []float64
+}
+
+func runParallelScene(t *testing.T, workers int) parallelRun {
+	s, err := SceneFromSceneDef(&SceneDef{Name: "parallel"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetUpdateWorkers(workers)
+	var run parallelRun
+	var movers []*mover
+	index := make(map[*Entity]int)
+	for i := 0; i < 200; i++ {
+		e := NewEntity()
+		index[e] = i
+		m := &mover{destroy: i%7 == 0}
+		movers = append(movers, m)
+		e.AddComponent(m)
+		if i%10 == 0 {
+			e.AddComponent(&scorer{score: &run.score, add: i})
+		}
+		if i%50 == 0 {
+			e.AddComponent(&counter{count: &run.count})
+		}
+		if i%4 == 0 {
+			e.AddComponent(&logger{log: &run.log, id: i, priority: -1})
+			e.AddComponent(&logger{log: &run.log, id: 1000 + i, priority: 1})
+		}
+		child := NewEntity()
+		childMover := &mover{}
+		movers = append(movers, childMover)
+		child.AddComponent(childMover)
+		e.AddChild(child)
+		s.AddEntity(e)
+	}
+	s.start()
+	s.awake()
+	for frame := 0; frame < 5; frame++ {
+		s.update(time.Millisecond)
+		for _, e := range s.destroyed {
+			if i, ok := index[e]; ok {
+				run.destroyed = append(run.destroyed, i)
+			}
+		}
+		s.flushDestroyed()
+	}
+	s.stop()
+	for _, m := range movers {
+		run.moved = append(run.moved, m.x)
+	}
+	return run
+}
+
+func TestParallelUpdateMatchesSerial(t *testing.T) {
+	serial := runParallelScene(t, 1)
+	parallel := runParallelScene(t, 4)
+	if serial.score != parallel.score {
+		t.Errorf("score %d in parallel, %d serially", parallel.score, serial.score)
+	}
+	if serial.count != parallel.count {
+		t.Errorf("counted %d in parallel, %d serially", parallel.count, serial.count)
+	}
+	if !reflect.DeepEqual(serial.log, parallel.log) {
+		t.Errorf("log in parallel\n%v\nserially\n%v", parallel.log, serial.log)
+	}
+	if !reflect.DeepEqual(serial.destroyed, parallel.destroyed) {
+		t.Errorf("destroyed in parallel %v, serially %v", parallel.destroyed, serial.destroyed)
+	}
+	if !reflect.DeepEqual(serial.moved, parallel.moved) {
+		t.Error("movers moved differently in parallel")
+	}
+	if len(serial.destroyed) == 0 || len(serial.log) == 0 {
+		t.Fatal("the scene destroyed or logged nothing, the comparison proves nothing")
+	}
+}
+
+//tinkerer : Changes its own entity every way Access allows besides Destroy
+type tinkerer struct {
+	BaseComponent
+	id    int
+	fired *[]int
+	extra *mover
+}
+
+func (c *tinkerer) UpdateAccess() Access {
+	return Access{}
+}
+
+func (c *tinkerer) Update(dur time.Duration) {
+	e := c.GetEntity()
+	e.AddTag("tinkered")
+	if e.HasTag("fresh") {
+		e.RemoveTag("fresh")
+	}
+	if c.extra == nil {
+		c.extra = &mover{}
+		e.AddComponent(c.extra)
+	} else {
+		e.RemoveComponent(c.extra)
+		c.extra = nil
+	}
+	e.SetLayer(fmt.Sprintf("tinkered%d", c.id%3))
+	id := c.id
+	e.After(time.Millisecond, func() {
+		*c.fired = append(*c.fired, id)
+	})
+}
+
+//tinkerRun : What tinkerers did to a scene
+type tinkerRun struct {
+	fired    []int
+	tinkered int
+	fresh    int
+	layers   []string
+	movers   int
+}
+
+func runTinkerScene(t *testing.T, workers int) tinkerRun {
+	s, err := SceneFromSceneDef(&SceneDef{Name: "tinker"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetUpdateWorkers(workers)
+	var run tinkerRun
+	var entities []*Entity
+	for i := 0; i < 100; i++ {
+		e := NewEntity()
+		e.AddTag("fresh")
+		e.AddComponent(&tinkerer{id: i, fired: &run.fired})
+		s.AddEntity(e)
+		entities = append(entities, e)
+	}
+	s.FindByTag("fresh")
+	s.start()
+	s.awake()
+	for frame := 0; frame < 3; frame++ {
+		s.update(time.Millisecond)
+		s.flushDestroyed()
+	}
+	run.tinkered = s.CountTag("tinkered")
+	run.fresh = s.CountTag("fresh")
+	for _, e := range entities {
+		run.layers = append(run.layers, e.Layer())
+		for _, c := range e.Components() {
+			if _, ok := c.(*mover); ok {
+				run.movers++
+			}
+		}
+	}
+	s.stop()
+	return run
+}
+
+func TestParallelComponentsChangeTheirTrees(t *testing.T) {
+	serial := runTinkerScene(t, 1)
+	parallel := runTinkerScene(t, 4)
+	if !reflect.DeepEqual(serial, parallel) {
+		t.Fatalf("in parallel %+v\nserially %+v", parallel, serial)
+	}
+	if serial.tinkered != 100 || serial.fresh != 0 {
+		t.Errorf("tagged %d entities and left %d fresh, want 100 and 0", serial.tinkered, serial.fresh)
+	}
+	if len(serial.fired) != 300 || serial.movers != 100 {
+		t.Errorf("fired %d timers and kept %d movers, want 300 and 100", len(serial.fired), serial.movers)
+	}
+}
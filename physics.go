@@ -2,6 +2,7 @@ package goldengine
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/vova616/chipmunk"
@@ -11,50 +12,174 @@ import (
 )
 
 //PhysicsEngineConfig : Configuration for the Physics Engine
+/*
+Defaults
+
+SubSteps: 1
+*/
 type PhysicsEngineConfig struct {
 	Debug   bool
 	Gravity vect.Vect
+	//SubSteps : Number of chipmunk steps taken every fixed update
+	SubSteps int
 }
 
-//PhysicsEngine : Wrapper around chipmunk engine
+//DefaultPhysicsSubSteps : One chipmunk step per fixed update
+const DefaultPhysicsSubSteps = 1
+
+//PhysicsEngine : Wrapper around chipmunk engine. Every scene in the scene
+//stack gets its own chipmunk space
 type PhysicsEngine struct {
 	debug         bool
 	debugEntities []*Entity
-	space         *chipmunk.Space
+	subSteps      int
+	gravity       vect.Vect
 	scene         *Scene
+	scenes        []*Scene
+	worlds        map[*Scene]*physicsWorld
+	profiler      *Profiler
 	BasicMailBox
 }
 
+//physicsWorld : Chipmunk space of a scene and the entities with bodies in it
+type physicsWorld struct {
+	space    *chipmunk.Space
+	entities map[uint32]*Entity
+}
+
 //RecieveMessage : RecieveMessage function for Physics Engine
 func (engine *PhysicsEngine) RecieveMessage(msg Message) {
 	switch msg.Message {
 	case SceneChangedMSG:
 		engine.ChangeScene(msg.Content.(*Scene))
+	case ScenePushedMSG:
+		engine.PushScene(msg.Content.(*SceneLayer).Scene)
+	case ScenePoppedMSG:
+		engine.PopScene(msg.Content.(*Scene))
+	case SceneReloadedMSG:
+		reload := msg.Content.(*SceneReload)
+		engine.ReplaceScene(reload.Old, reload.New)
 	case SceneAddedEntityMSG:
 		engine.AddEntity(msg.Content.(*Entity))
+	case SceneRemovedEntityMSG, SceneDisabledEntityMSG:
+		engine.RemoveEntity(msg.Content.(*Entity))
+	case SceneEnabledEntityMSG:
+		engine.AddEntity(msg.Content.(*Entity))
 	}
 }
 
-//ChangeScene : Changes the Current Scene
+//ChangeScene : Changes the Current Scene. Every other scene leaves the engine
 func (engine *PhysicsEngine) ChangeScene(s *Scene) {
 	//TODO : Remove all debug components
+	for len(engine.scenes) > 0 {
+		engine.PopScene(engine.scenes[len(engine.scenes)-1])
+	}
+	engine.PushScene(s)
+}
 
-	office := engine.GetOffice()
-	office.Subscribe(s.GetAddress(), engine.GetAddress(), SceneAddedEntityMSG)
+//PushScene : Gives a scene its own space on top of the others
+func (engine *PhysicsEngine) PushScene(s *Scene) {
+	if _, ok := engine.worlds[s]; ok {
+		return
+	}
+	space := chipmunk.NewSpace()
+	space.Gravity = engine.gravity
+	engine.worlds[s] = &physicsWorld{
+		space:    space,
+		entities: make(map[uint32]*Entity),
+	}
+	engine.scenes = append(engine.scenes, s)
 	engine.scene = s
+	engine.GetOffice().Subscribe(s.GetAddress(), engine.GetAddress(), SceneAddedEntityMSG)
+	engine.GetOffice().Subscribe(s.GetAddress(), engine.GetAddress(), SceneRemovedEntityMSG)
+	engine.GetOffice().Subscribe(s.GetAddress(), engine.GetAddress(), SceneEnabledEntityMSG)
+	engine.GetOffice().Subscribe(s.GetAddress(), engine.GetAddress(), SceneDisabledEntityMSG)
 	engine.AddEntities(s.GetEntities())
 }
 
-//AddEntity : Add Entity to phsics space
+//PopScene : Removes a scene and its space
+func (engine *PhysicsEngine) PopScene(s *Scene) {
+	world, ok := engine.worlds[s]
+	if !ok {
+		return
+	}
+	engine.GetOffice().UnSubscribe(s.GetAddress(), engine.GetAddress(), SceneAddedEntityMSG)
+	engine.GetOffice().UnSubscribe(s.GetAddress(), engine.GetAddress(), SceneRemovedEntityMSG)
+	engine.GetOffice().UnSubscribe(s.GetAddress(), engine.GetAddress(), SceneEnabledEntityMSG)
+	engine.GetOffice().UnSubscribe(s.GetAddress(), engine.GetAddress(), SceneDisabledEntityMSG)
+	for _, e := range world.entities {
+		world.space.RemoveBody(e.Collider)
+	}
+	delete(engine.worlds, s)
+	for i, scene := range engine.scenes {
+		if scene == s {
+			engine.scenes = append(engine.scenes[:i], engine.scenes[i+1:]...)
+			break
+		}
+	}
+	engine.scene = nil
+	if len(engine.scenes) > 0 {
+		engine.scene = engine.scenes[len(engine.scenes)-1]
+	}
+}
+
+//AddEntity : Add Entity to the space of its scene
 func (engine *PhysicsEngine) AddEntity(e *Entity) {
-	if e != nil && e.Collider != nil {
-		engine.space.AddBody(e.Collider)
+	if e == nil {
+		return
+	}
+	scene := e.scene
+	if scene == nil {
+		scene = engine.scene
+	}
+	world, ok := engine.worlds[scene]
+	if ok && e.Collider != nil && e.ActiveInHierarchy() {
+		if _, added := world.entities[e.id]; added {
+			return
+		}
+		if e.Transfrom != nil {
+			e.Collider.SetPosition(Vector2fToChipmunk(e.WorldPosition()))
+			e.Collider.SetAngle(vect.Float(e.WorldRotation() * math.Pi / 180))
+		}
+		e.storeBodyState()
+		e.applyCollisionLayer()
+		world.space.AddBody(e.Collider)
+		world.entities[e.id] = e
 	}
 	if engine.debug {
 		engine.makeDebugEntity(e)
 	}
 }
 
+//RemoveEntity : Removes an entity's body from the space of its scene
+func (engine *PhysicsEngine) RemoveEntity(e *Entity) {
+	for _, world := range engine.worlds {
+		if _, ok := world.entities[e.id]; ok {
+			world.space.RemoveBody(e.Collider)
+			delete(world.entities, e.id)
+		}
+	}
+}
+
+//ReplaceScene : Gives to the place of from in the engine, with a fresh space of its own
+func (engine *PhysicsEngine) ReplaceScene(from, to *Scene) {
+	index := -1
+	for i, s := range engine.scenes {
+		if s == from {
+			index = i
+		}
+	}
+	if index < 0 {
+		return
+	}
+	engine.PopScene(from)
+	engine.PushScene(to)
+	last := len(engine.scenes) - 1
+	copy(engine.scenes[index+1:], engine.scenes[index:last])
+	engine.scenes[index] = to
+	engine.scene = engine.scenes[last]
+}
+
 //AddEntities : Convienece function to add a bunch of entities
 func (engine *PhysicsEngine) AddEntities(list []*Entity) {
 	for _, e := range list {
@@ -63,7 +188,11 @@ func (engine *PhysicsEngine) AddEntities(list []*Entity) {
 }
 
 func (engine *PhysicsEngine) makeDebugEntity(e *Entity) {
-	if engine.scene == nil || e.Collider == nil {
+	scene := e.scene
+	if scene == nil {
+		scene = engine.scene
+	}
+	if scene == nil || e.Collider == nil {
 		fmt.Println(e.Collider)
 		return
 	}
@@ -87,8 +216,8 @@ func (engine *PhysicsEngine) makeDebugEntity(e *Entity) {
 			debug := NewEntity()
 			debug.Transfrom = circle
 			debugRoot.AddChild(debug)
-			engine.scene.AddEntity(debug)
-			engine.scene.SetZIndex(debug.Name, 1)
+			scene.AddEntity(debug)
+			scene.SetZIndex(debug.Name, 1)
 		case *chipmunk.BoxShape:
 			rectangle, err := sf.NewRectangleShape()
 			if err != nil {
@@ -104,7 +233,7 @@ func (engine *PhysicsEngine) makeDebugEntity(e *Entity) {
 			debugRoot.AddChild(debug)
 		}
 	}
-	engine.scene.AddEntity(debugRoot)
+	scene.AddEntity(debugRoot)
 }
 
 //SetDebug : Sets Debug mode of Physics Engine
@@ -112,21 +241,61 @@ func (engine *PhysicsEngine) SetDebug(debug bool) {
 	engine.debug = debug
 }
 
-//GetSpace : Get chipmunk space
+//GetSpace : Get chipmunk space of the top scene
 func (engine *PhysicsEngine) GetSpace() *chipmunk.Space {
-	return engine.space
+	return engine.GetSceneSpace(engine.scene)
 }
 
-func (engine *PhysicsEngine) step(dur time.Duration) {
-	engine.space.Step(vect.Float(dur.Seconds()))
+//GetSceneSpace : Get chipmunk space of a scene. nil if the scene isn't in the engine
+func (engine *PhysicsEngine) GetSceneSpace(s *Scene) *chipmunk.Space {
+	world, ok := engine.worlds[s]
+	if !ok {
+		return nil
+	}
+	return world.space
+}
+
+//step : Advances the space of a scene by dur split into engine.subSteps steps
+func (engine *PhysicsEngine) step(s *Scene, dur time.Duration) {
+	world, ok := engine.worlds[s]
+	if !ok {
+		return
+	}
+	start := engine.profiler.now()
+	defer engine.profiler.end("physics", "Physics "+s.Name, start, profileLoopThread)
+	layerVersion := LayerRegister.currentVersion()
+	for _, e := range world.entities {
+		e.storeBodyState()
+		if e.layerVersion != layerVersion {
+			e.applyCollisionLayer()
+		}
+	}
+	dt := vect.Float(dur.Seconds()) / vect.Float(engine.subSteps)
+	for i := 0; i < engine.subSteps; i++ {
+		world.space.Step(dt)
+	}
+}
+
+//interpolate : Moves the transforms of bodies between their last two states
+func (engine *PhysicsEngine) interpolate(alpha float32) {
+	for _, world := range engine.worlds {
+		for _, e := range world.entities {
+			e.interpolateBody(alpha)
+		}
+	}
 }
 
 //PhysicsEngineFromConfig : Generates a PhysicsEngine from Config
 func newPhysicsEngine(config PhysicsEngineConfig) *PhysicsEngine {
+	subSteps := config.SubSteps
+	if subSteps <= 0 {
+		subSteps = DefaultPhysicsSubSteps
+	}
 	engine := &PhysicsEngine{
-		debug: config.Debug,
-		space: chipmunk.NewSpace(),
+		debug:    config.Debug,
+		subSteps: subSteps,
+		gravity:  config.Gravity,
+		worlds:   make(map[*Scene]*physicsWorld),
 	}
-	engine.space.Gravity = config.Gravity
 	return engine
 }
@@ -15,25 +15,96 @@ var PrefabRegister = prefabRegister{
 }
 
 func (register *prefabRegister) RegisterFromFile(location string) error {
+	_, err := register.registerFile(location)
+	return err
+}
+
+func (register *prefabRegister) RegisterFromData(dat []byte) error {
+	_, err := register.registerData(dat)
+	return err
+}
+
+//registerFile : Registers a prefab file and returns the prefab's name
+func (register *prefabRegister) registerFile(location string) (string, error) {
 	dat, err := ioutil.ReadFile(location)
 	if err != nil {
-		return err
+		return "", err
 	}
-	return register.RegisterFromData(dat)
+	return register.registerData(dat)
 }
 
-func (register *prefabRegister) RegisterFromData(dat []byte) error {
+func (register *prefabRegister) registerData(dat []byte) (string, error) {
 	var prefab EntityPrefab
 	err := json.Unmarshal(dat, &prefab)
 	if err != nil {
-		return err
+		return "", err
 	}
 	name := prefab.Name
 	register.register[name] = prefab
-	return nil
+	return name, nil
 }
 
+//Get : Copy of a registered prefab. Changing it doesn't change the prefab
 func (register *prefabRegister) Get(name string) (EntityPrefab, bool) {
 	p, ok := register.register[name]
-	return p, ok
+	if !ok {
+		return p, ok
+	}
+	return p.Copy(), ok
+}
+
+//Copy : Deep copy of the prefab, arguments included
+func (prefab EntityPrefab) Copy() EntityPrefab {
+	prefab.Transformer.Arguments = copyArguments(prefab.Transformer.Arguments)
+	prefab.Collider.Arguments = copyArguments(prefab.Collider.Arguments)
+	components := make([]ComponentPrefab, len(prefab.Components))
+	for i, c := range prefab.Components {
+		components[i] = ComponentPrefab{
+			Name:      c.Name,
+			Arguments: copyArguments(c.Arguments),
+		}
+	}
+	prefab.Components = components
+	prefab.Tags = append([]string(nil), prefab.Tags...)
+	return prefab
+}
+
+//copyArguments : Deep copy of arguments from the JSON parser
+func copyArguments(args map[string]interface{}) map[string]interface{} {
+	if args == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		copied[k] = copyArgument(v)
+	}
+	return copied
+}
+
+func copyArgument(arg interface{}) interface{} {
+	switch value := arg.(type) {
+	case map[string]interface{}:
+		return copyArguments(value)
+	case []interface{}:
+		list := make([]interface{}, len(value))
+		for i, v := range value {
+			list[i] = copyArgument(v)
+		}
+		return list
+	}
+	return arg
+}
+
+//mergeArguments : args with overrides copied over it. args is changed unless it's nil
+func mergeArguments(args, overrides map[string]interface{}) map[string]interface{} {
+	if len(overrides) == 0 {
+		return args
+	}
+	if args == nil {
+		args = make(map[string]interface{}, len(overrides))
+	}
+	for k, v := range overrides {
+		args[k] = copyArgument(v)
+	}
+	return args
 }
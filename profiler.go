@@ -0,0 +1,313 @@
+package goldengine
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+//Trace threads. Chrome shows every thread on its own row
+const (
+	profileLoopThread    = 1
+	profileRenderThread  = 2
+	profileMessageThread = 3
+)
+
+//DefaultProfilerWindow : Frames kept for the rolling summary
+const DefaultProfilerWindow = 300
+
+//DefaultProfilerMaxEvents : Trace events kept before new ones are dropped
+const DefaultProfilerMaxEvents = 1 << 20
+
+//Profiler : Records how long frames, entities, components, drawing, physics
+//and messages take. Exports Chrome trace event JSON and keeps a rolling
+//summary of the last frames. A nil Profiler records nothing
+type Profiler struct {
+	//Window : Frames kept for the rolling summary
+	Window int
+	//MaxEvents : Trace events kept before new ones are dropped
+	MaxEvents int
+
+	start   time.Time
+	events  []traceEvent
+	dropped int
+	frames  []frameSample
+	next    int
+	current map[string]componentSample
+	mu      sync.Mutex
+}
+
+//traceEvent : Complete event of the Chrome trace event format
+type traceEvent struct {
+	Name     string  `json:"name"`
+	Category string  `json:"cat"`
+	Phase    string  `json:"ph"`
+	Time     float64 `json:"ts"`
+	Duration float64 `json:"dur"`
+	Process  int     `json:"pid"`
+	Thread   int     `json:"tid"`
+}
+
+type frameSample struct {
+	duration   time.Duration
+	components map[string]componentSample
+}
+
+type componentSample struct {
+	calls int
+	total time.Duration
+	max   time.Duration
+}
+
+//ProfileSummary : Timings of the last frames
+type ProfileSummary struct {
+	Frames   int
+	FrameP50 time.Duration
+	FrameP99 time.Duration
+	FrameMax time.Duration
+	//Components : Slowest component types first, by total time
+	Components []ComponentTiming
+}
+
+//ComponentTiming : Time spent in the Update of one component type
+type ComponentTiming struct {
+	Type    string
+	Calls   int
+	Total   time.Duration
+	Average time.Duration
+	Max     time.Duration
+}
+
+//NewProfiler : Creates a Profiler with the default window and event limit
+func NewProfiler() *Profiler {
+	return &Profiler{
+		Window:    DefaultProfilerWindow,
+		MaxEvents: DefaultProfilerMaxEvents,
+		start:     time.Now(),
+		current:   make(map[string]componentSample),
+	}
+}
+
+//now : Start time of a measurement. Zero for a nil Profiler
+func (p *Profiler) now() time.Time {
+	if p == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+//end : Records an event that began at start
+func (p *Profiler) end(category, name string, start time.Time, thread int) {
+	if p == nil {
+		return
+	}
+	dur := time.Since(start)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.addEvent(category, name, start, dur, thread)
+}
+
+func (p *Profiler) addEvent(category, name string, start time.Time, dur time.Duration, thread int) {
+	if len(p.events) >= p.MaxEvents {
+		p.dropped++
+		return
+	}
+	p.events = append(p.events, traceEvent{
+		Name:     name,
+		Category: category,
+		Phase:    "X",
+		Time:     float64(start.Sub(p.start).Nanoseconds()) / 1000,
+		Duration: float64(dur.Nanoseconds()) / 1000,
+		Process:  1,
+		Thread:   thread,
+	})
+}
+
+//component : Records a component Update that began at start
+func (p *Profiler) component(c Component, start time.Time) {
+	if p == nil {
+		return
+	}
+	dur := time.Since(start)
+	name := reflect.TypeOf(c).String()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.addEvent("component", name, start, dur, profileLoopThread)
+	sample := p.current[name]
+	sample.calls++
+	sample.total += dur
+	if dur > sample.max {
+		sample.max = dur
+	}
+	p.current[name] = sample
+}
+
+//system : Records a hook of a System that began at start
+func (p *Profiler) system(system System, hook string, start time.Time) {
+	if p == nil {
+		return
+	}
+	p.end("system", reflect.TypeOf(system).String()+"."+hook, start, profileLoopThread)
+}
+
+//frame : Records a fixed update that began at start and closes its sample
+func (p *Profiler) frame(start time.Time) {
+	if p == nil {
+		return
+	}
+	dur := time.Since(start)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.addEvent("frame", "Frame", start, dur, profileLoopThread)
+	sample := frameSample{
+		duration:   dur,
+		components: p.current,
+	}
+	p.current = make(map[string]componentSample)
+	if len(p.frames) < p.Window {
+		p.frames = append(p.frames, sample)
+		return
+	}
+	p.frames[p.next%len(p.frames)] = sample
+	p.next++
+}
+
+//Summary : Frame percentiles and component timings over the rolling window
+func (p *Profiler) Summary() ProfileSummary {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	summary := ProfileSummary{
+		Frames: len(p.frames),
+	}
+	if len(p.frames) == 0 {
+		return summary
+	}
+	durations := make([]time.Duration, len(p.frames))
+	totals := make(map[string]componentSample)
+	for i, frame := range p.frames {
+		durations[i] = frame.duration
+		for name, sample := range frame.components {
+			total := totals[name]
+			total.calls += sample.calls
+			total.total += sample.total
+			if sample.max > total.max {
+				total.max = sample.max
+			}
+			totals[name] = total
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	summary.FrameP50 = percentile(durations, 50)
+	summary.FrameP99 = percentile(durations, 99)
+	summary.FrameMax = durations[len(durations)-1]
+	for name, total := range totals {
+		summary.Components = append(summary.Components, ComponentTiming{
+			Type:    name,
+			Calls:   total.calls,
+			Total:   total.total,
+			Average: total.total / time.Duration(total.calls),
+			Max:     total.max,
+		})
+	}
+	sort.Slice(summary.Components, func(i, j int) bool {
+		if summary.Components[i].Total != summary.Components[j].Total {
+			return summary.Components[i].Total > summary.Components[j].Total
+		}
+		return summary.Components[i].Type < summary.Components[j].Type
+	})
+	return summary
+}
+
+//percentile : Nearest rank percentile of sorted durations
+func percentile(sorted []time.Duration, pct int) time.Duration {
+	rank := (pct*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+//Dropped : Trace events dropped after MaxEvents was reached
+func (p *Profiler) Dropped() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.dropped
+}
+
+//Reset : Forgets every event and frame
+func (p *Profiler) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.start = time.Now()
+	p.events = nil
+	p.dropped = 0
+	p.frames = nil
+	p.next = 0
+	p.current = make(map[string]componentSample)
+}
+
+//WriteTrace : Writes recorded events as Chrome trace event JSON.
+//Open it in chrome://tracing or Perfetto
+func (p *Profiler) WriteTrace(w io.Writer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	events := p.events
+	if events == nil {
+		events = []traceEvent{}
+	}
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}{events})
+}
+
+//SaveTrace : Writes recorded events to a Chrome trace event JSON file
+func (p *Profiler) SaveTrace(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = p.WriteTrace(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+//EnableProfiler : Starts profiling the game and returns the Profiler
+func (g *Game) EnableProfiler() *Profiler {
+	if g.profiler == nil {
+		g.setProfiler(NewProfiler())
+	}
+	return g.profiler
+}
+
+//DisableProfiler : Stops profiling the game
+func (g *Game) DisableProfiler() {
+	g.setProfiler(nil)
+}
+
+//Profiler : The game's Profiler. nil unless EnableProfiler was called
+func (g *Game) Profiler() *Profiler {
+	return g.profiler
+}
+
+func (g *Game) setProfiler(p *Profiler) {
+	g.profiler = p
+	g.PostOffice.profiler = p
+	g.window.profiler = p
+	g.physicsEngine.profiler = p
+}
+
+//profiler : Profiler of the scene's game. nil if there isn't one
+func (s *Scene) profiler() *Profiler {
+	if s == nil || s.game == nil {
+		return nil
+	}
+	return s.game.profiler
+}
@@ -0,0 +1,68 @@
+package goldengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+//traceEntities : Times each entity name was recorded in a Chrome trace
+func traceEntities(t *testing.T, profiler *Profiler) map[string]int {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := profiler.WriteTrace(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatal(err)
+	}
+	entities := make(map[string]int)
+	for _, event := range trace.TraceEvents {
+		if event.Category == "entity" {
+			entities[event.Name]++
+		}
+	}
+	return entities
+}
+
+func TestTraceRecordsEntities(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		game := newTestGame(t, GameConfig{TimeStep: 10 * time.Millisecond}, nil, map[string]string{
+			"scenes/main.scene": `{"Name":"main"}`,
+		})
+		game.ChangeScene("main")
+		scene := game.GetCurrentScene()
+		scene.SetUpdateWorkers(workers)
+		var count int
+		for _, name := range []string{"player", "enemy"} {
+			e := NewEntity()
+			e.Name = name
+			e.AddComponent(&counter{count: &count})
+			e.AddComponent(&mover{})
+			scene.AddEntity(e)
+		}
+		sleeper := NewEntity()
+		sleeper.Name = "sleeper"
+		sleeper.AddComponent(&mover{})
+		scene.AddEntity(sleeper)
+		sleeper.SetEnabled(false)
+
+		game.Start()
+		profiler := game.EnableProfiler()
+		game.Tick(30 * time.Millisecond)
+		game.Stop()
+		entities := traceEntities(t, profiler)
+		for _, name := range []string{"player", "enemy"} {
+			if entities[name] < 3 {
+				t.Errorf("%d workers: %s recorded %d times in 3 steps, want at least 3", workers, name, entities[name])
+			}
+		}
+		if entities["sleeper"] != 0 {
+			t.Errorf("%d workers: disabled entity recorded %d times", workers, entities["sleeper"])
+		}
+	}
+}
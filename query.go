@@ -0,0 +1,383 @@
+package goldengine
+
+import (
+	"fmt"
+	"sort"
+	"unicode"
+)
+
+//Selector : Parsed entity selector
+/*
+	leftPaddle           entity named leftPaddle
+	#leftPaddle          entity named leftPaddle
+	.enemy               entities tagged enemy
+	[component=paddle]   entities with a component registered as paddle
+	[prefab=ball]        entities made from the ball prefab
+	*                    every entity
+	hud > scoreLabel     scoreLabel that is a child of hud
+	hud scoreLabel       scoreLabel anywhere below hud
+
+Parts can be combined, #ball.enemy[component=bounce] matches all three.
+*/
+type Selector struct {
+	source string
+	parts  []selectorPart
+}
+
+//selectorPart : One compound selector and how it relates to the part before it
+type selectorPart struct {
+	//child is true for ">" and false for a descendant
+	child bool
+	name  string
+	tags  []string
+	attrs []selectorAttr
+}
+
+type selectorAttr struct {
+	key   string
+	value string
+}
+
+//ParseSelector : Parses a selector. See Selector for the syntax
+func ParseSelector(selector string) (*Selector, error) {
+	p := selectorParser{
+		source: selector,
+		runes:  []rune(selector),
+	}
+	parts, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Selector{
+		source: selector,
+		parts:  parts,
+	}, nil
+}
+
+//MustParseSelector : ParseSelector that panics on bad selectors
+func MustParseSelector(selector string) *Selector {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//String : The selector as it was written
+func (sel *Selector) String() string {
+	return sel.source
+}
+
+//Matches : Entity matches the selector
+func (sel *Selector) Matches(e *Entity) bool {
+	return sel.matches(e, len(sel.parts)-1)
+}
+
+func (sel *Selector) matches(e *Entity, index int) bool {
+	part := sel.parts[index]
+	if !part.matches(e) {
+		return false
+	}
+	if index == 0 {
+		return true
+	}
+	if part.child {
+		return e.parent != nil && sel.matches(e.parent, index-1)
+	}
+	for parent := e.parent; parent != nil; parent = parent.parent {
+		if sel.matches(parent, index-1) {
+			return true
+		}
+	}
+	return false
+}
+
+func (part selectorPart) matches(e *Entity) bool {
+	if part.name != "" && e.Name != part.name {
+		return false
+	}
+	for _, tag := range part.tags {
+		if !e.HasTag(tag) {
+			return false
+		}
+	}
+	for _, attr := range part.attrs {
+		switch attr.key {
+		case "component":
+			if !e.hasComponentNamed(attr.value) {
+				return false
+			}
+		case "prefab":
+			if e.prefab != attr.value {
+				return false
+			}
+		case "name":
+			if e.Name != attr.value {
+				return false
+			}
+		case "tag":
+			if !e.HasTag(attr.value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//selectorAttrKeys : Attributes a selector can test
+var selectorAttrKeys = map[string]bool{
+	"component": true,
+	"prefab":    true,
+	"name":      true,
+	"tag":       true,
+}
+
+type selectorParser struct {
+	source string
+	runes  []rune
+	pos    int
+}
+
+func (p *selectorParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("Selector %q at %d: %s", p.source, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *selectorParser) skipSpace() {
+	for p.pos < len(p.runes) && unicode.IsSpace(p.runes[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *selectorParser) done() bool {
+	return p.pos >= len(p.runes)
+}
+
+func (p *selectorParser) parse() ([]selectorPart, error) {
+	var parts []selectorPart
+	p.skipSpace()
+	if p.done() {
+		return nil, p.errorf("empty selector")
+	}
+	for !p.done() {
+		child := false
+		if len(parts) > 0 {
+			p.skipSpace()
+			if p.runes[p.pos] == '>' {
+				child = true
+				p.pos++
+				p.skipSpace()
+			}
+			if p.done() {
+				return nil, p.errorf("selector ends with a combinator")
+			}
+		}
+		part, err := p.parseCompound()
+		if err != nil {
+			return nil, err
+		}
+		part.child = child
+		parts = append(parts, part)
+		p.skipSpace()
+	}
+	return parts, nil
+}
+
+//parseCompound : Name, #name, .tag, [key=value] and * with nothing in between
+func (p *selectorParser) parseCompound() (selectorPart, error) {
+	var part selectorPart
+	start := p.pos
+	for !p.done() {
+		r := p.runes[p.pos]
+		switch {
+		case r == '*':
+			p.pos++
+		case r == '#':
+			p.pos++
+			name := p.parseIdent()
+			if name == "" {
+				return part, p.errorf("expected a name after #")
+			}
+			if part.name != "" && part.name != name {
+				return part, p.errorf("an entity has one name")
+			}
+			part.name = name
+		case r == '.':
+			p.pos++
+			tag := p.parseIdent()
+			if tag == "" {
+				return part, p.errorf("expected a tag after .")
+			}
+			part.tags = append(part.tags, tag)
+		case r == '[':
+			p.pos++
+			attr, err := p.parseAttr()
+			if err != nil {
+				return part, err
+			}
+			part.attrs = append(part.attrs, attr)
+		case isSelectorIdent(r):
+			if p.pos != start {
+				return part, p.errorf("a name must come first")
+			}
+			part.name = p.parseIdent()
+		default:
+			if p.pos == start {
+				return part, p.errorf("unexpected %q", r)
+			}
+			return part, nil
+		}
+	}
+	return part, nil
+}
+
+//parseAttr : key=value] with an optionally quoted value
+func (p *selectorParser) parseAttr() (selectorAttr, error) {
+	var attr selectorAttr
+	p.skipSpace()
+	attr.key = p.parseIdent()
+	if !selectorAttrKeys[attr.key] {
+		return attr, p.errorf("unknown attribute %q", attr.key)
+	}
+	p.skipSpace()
+	if p.done() || p.runes[p.pos] != '=' {
+		return attr, p.errorf("expected =")
+	}
+	p.pos++
+	p.skipSpace()
+	if !p.done() && (p.runes[p.pos] == '"' || p.runes[p.pos] == '\'') {
+		quote := p.runes[p.pos]
+		p.pos++
+		end := p.pos
+		for end < len(p.runes) && p.runes[end] != quote {
+			end++
+		}
+		if end == len(p.runes) {
+			return attr, p.errorf("unterminated string")
+		}
+		attr.value = string(p.runes[p.pos:end])
+		p.pos = end + 1
+	} else {
+		attr.value = p.parseIdent()
+	}
+	if attr.value == "" {
+		return attr, p.errorf("expected a value for %s", attr.key)
+	}
+	p.skipSpace()
+	if p.done() || p.runes[p.pos] != ']' {
+		return attr, p.errorf("expected ]")
+	}
+	p.pos++
+	return attr, nil
+}
+
+func (p *selectorParser) parseIdent() string {
+	start := p.pos
+	for !p.done() && isSelectorIdent(p.runes[p.pos]) {
+		p.pos++
+	}
+	return string(p.runes[start:p.pos])
+}
+
+func isSelectorIdent(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+}
+
+//Query : Entities that matched a selector, in the order they were created.
+//Iterate with Next and Entity
+/*
+	q := scene.Query(".enemy")
+	for q.Next() {
+		q.Entity().Destroy()
+	}
+	if q.Err() != nil {
+		...
+	}
+*/
+type Query struct {
+	entities []*Entity
+	index    int
+	err      error
+}
+
+//Next : Moves to the next entity. False once there are none left
+func (q *Query) Next() bool {
+	if q.index >= len(q.entities) {
+		return false
+	}
+	q.index++
+	return true
+}
+
+//Entity : Entity the query is on
+func (q *Query) Entity() *Entity {
+	if q.index == 0 || q.index > len(q.entities) {
+		return nil
+	}
+	return q.entities[q.index-1]
+}
+
+//Err : Why the selector couldn't be parsed
+func (q *Query) Err() error {
+	return q.err
+}
+
+//All : Every matching entity
+func (q *Query) All() []*Entity {
+	list := make([]*Entity, len(q.entities))
+	copy(list, q.entities)
+	return list
+}
+
+//First : First matching entity
+func (q *Query) First() (*Entity, bool) {
+	if len(q.entities) == 0 {
+		return nil, false
+	}
+	return q.entities[0], true
+}
+
+//Len : Number of matching entities
+func (q *Query) Len() int {
+	return len(q.entities)
+}
+
+//Query : Entities of the scene matching a selector. See Selector for the
+//syntax. Destroyed entities are skipped
+func (s *Scene) Query(selector string) *Query {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return &Query{err: err}
+	}
+	return s.QuerySelector(sel)
+}
+
+//QuerySelector : Query with an already parsed selector
+func (s *Scene) QuerySelector(sel *Selector) *Query {
+	var candidates []*Entity
+	last := sel.parts[len(sel.parts)-1]
+	if last.name != "" {
+		if e, ok := s.GetEntityByName(last.name); ok {
+			candidates = append(candidates, e)
+		}
+	} else if len(last.tags) > 0 {
+		candidates = s.FindByTag(last.tags[0])
+	} else {
+		candidates = s.GetEntities()
+	}
+	q := &Query{}
+	for _, e := range candidates {
+		if !e.destroyed && sel.Matches(e) {
+			q.entities = append(q.entities, e)
+		}
+	}
+	sort.Slice(q.entities, func(i, j int) bool {
+		return q.entities[i].id < q.entities[j].id
+	})
+	return q
+}
+
+//QueryOne : First entity of the scene matching a selector
+func (s *Scene) QueryOne(selector string) (*Entity, bool) {
+	return s.Query(selector).First()
+}
+
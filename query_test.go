@@ -0,0 +1,149 @@
+package goldengine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		parts    []selectorPart
+	}{
+		{"ball", []selectorPart{{name: "ball"}}},
+		{"#ball", []selectorPart{{name: "ball"}}},
+		{".enemy", []selectorPart{{tags: []string{"enemy"}}}},
+		{"*", []selectorPart{{}}},
+		{"[component=paddle]", []selectorPart{{attrs: []selectorAttr{{"component", "paddle"}}}}},
+		{"[prefab='big ball']", []selectorPart{{attrs: []selectorAttr{{"prefab", "big ball"}}}}},
+		{`[ name = "left" ]`, []selectorPart{{attrs: []selectorAttr{{"name", "left"}}}}},
+		{"#ball.enemy.fast[component=bounce]", []selectorPart{{
+			name:  "ball",
+			tags:  []string{"enemy", "fast"},
+			attrs: []selectorAttr{{"component", "bounce"}},
+		}}},
+		{"hud > scoreLabel", []selectorPart{{name: "hud"}, {child: true, name: "scoreLabel"}}},
+		{"hud>scoreLabel", []selectorPart{{name: "hud"}, {child: true, name: "scoreLabel"}}},
+		{"hud scoreLabel", []selectorPart{{name: "hud"}, {name: "scoreLabel"}}},
+		{"  hud \t .label\n> *  ", []selectorPart{{name: "hud"}, {tags: []string{"label"}}, {child: true}}},
+	}
+	for _, test := range tests {
+		sel, err := ParseSelector(test.selector)
+		if err != nil {
+			t.Errorf("%q: %v", test.selector, err)
+			continue
+		}
+		if !reflect.DeepEqual(sel.parts, test.parts) {
+			t.Errorf("%q parsed to %+v, want %+v", test.selector, sel.parts, test.parts)
+		}
+		if sel.String() != test.selector {
+			t.Errorf("%q String %q", test.selector, sel.String())
+		}
+	}
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	for _, selector := range []string{
+		"",
+		"   ",
+		"#",
+		".",
+		"a#b",
+		"#a#b",
+		"> a",
+		"a >",
+		"a > > b",
+		"a, b",
+		"[",
+		"[]",
+		"[colour=red]",
+		"[tag]",
+		"[tag=]",
+		"[tag=red",
+		"[tag='red]",
+		"[tag=red)",
+		"a]",
+	} {
+		if sel, err := ParseSelector(selector); err == nil {
+			t.Errorf("%q parsed to %+v, want an error", selector, sel.parts)
+		}
+	}
+}
+
+func TestParseSelectorPrefixes(t *testing.T) {
+	//Every prefix of a selector parses or errors, none panics
+	for _, selector := range []string{
+		`hud > #score.label[component="text"] [prefab='digit']`,
+		"a.b.c > d [tag=e]",
+	} {
+		runes := []rune(selector)
+		for i := range runes {
+			ParseSelector(string(runes[:i]))
+		}
+	}
+}
+
+func TestSceneQuery(t *testing.T) {
+	s, err := SceneFromSceneDef(&SceneDef{Name: "query"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	named := func(name string, tags ...string) *Entity {
+		e := NewEntity()
+		e.Name = name
+		for _, tag := range tags {
+			e.AddTag(tag)
+		}
+		return e
+	}
+	hud := named("hud", "ui")
+	panel := named("panel", "ui")
+	score := named("score", "ui", "label")
+	lives := named("lives", "label")
+	ball := named("ball", "enemy")
+	ball.addNamedComponent("testEngine", &testEngine{})
+	hud.AddChild(panel)
+	panel.AddChild(score)
+	hud.AddChild(lives)
+	s.AddEntity(hud)
+	s.AddEntity(ball)
+
+	tests := []struct {
+		selector string
+		want     []*Entity
+	}{
+		{"#score", []*Entity{score}},
+		{".label", []*Entity{score, lives}},
+		{".ui.label", []*Entity{score}},
+		{"[component=testEngine]", []*Entity{ball}},
+		{"[tag=enemy]", []*Entity{ball}},
+		{"hud > .label", []*Entity{lives}},
+		{"hud .label", []*Entity{score, lives}},
+		{"hud > panel > score", []*Entity{score}},
+		{"panel > *", []*Entity{score}},
+		{".ui > .ui", []*Entity{panel, score}},
+		{"ball > *", nil},
+		{"#missing", nil},
+	}
+	for _, test := range tests {
+		q := s.Query(test.selector)
+		if q.Err() != nil {
+			t.Errorf("%q: %v", test.selector, q.Err())
+			continue
+		}
+		if got := q.All(); !reflect.DeepEqual(got, test.want) && (len(got) != 0 || len(test.want) != 0) {
+			t.Errorf("%q found %v, want %v", test.selector, entityNames(got), entityNames(test.want))
+		}
+	}
+	if q := s.Query("hud >"); q.Err() == nil || q.Len() != 0 {
+		t.Errorf("malformed query found %d entities with error %v", q.Len(), q.Err())
+	}
+}
+
+func entityNames(list []*Entity) []string {
+	names := make([]string, len(list))
+	for i, e := range list {
+		names[i] = e.Name
+	}
+	return names
+}
@@ -0,0 +1,21 @@
+package goldengine
+
+import sf "github.com/manyminds/gosfml"
+
+//Renderer : Anything a scene can be drawn onto.
+//sf.RenderTarget and every WindowBackend are Renderers
+type Renderer interface {
+	Draw(drawer sf.Drawer, renderStates sf.RenderStates)
+}
+
+//WindowBackend : Platform window a Window gets events from and draws to.
+//*sf.RenderWindow is a WindowBackend
+type WindowBackend interface {
+	Renderer
+	Clear(color sf.Color)
+	Display()
+	GetSize() sf.Vector2u
+	IsOpen() bool
+	Close()
+	PollEvent() sf.Event
+}
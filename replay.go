@@ -0,0 +1,160 @@
+package goldengine
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//InputEvent : Input message stamped with the fixed update it was applied before
+type InputEvent struct {
+	Frame   uint64
+	Message MessageType
+	Code    sf.KeyCode
+}
+
+//InputRecordingHeader : First line of an input recording. Holds what a replay
+//needs besides the input to reproduce a run
+type InputRecordingHeader struct {
+	Seed     int64
+	TimeStep time.Duration
+}
+
+//InputRecorder : Writes input events as JSON lines. The first line is an InputRecordingHeader
+type InputRecorder struct {
+	encoder *json.Encoder
+	closer  io.Closer
+	err     error
+	mu      sync.Mutex
+}
+
+//NewInputRecorder : Writes the header and returns a recorder writing to w
+func NewInputRecorder(w io.Writer, header InputRecordingHeader) (*InputRecorder, error) {
+	recorder := &InputRecorder{
+		encoder: json.NewEncoder(w),
+	}
+	if closer, ok := w.(io.Closer); ok {
+		recorder.closer = closer
+	}
+	if err := recorder.encoder.Encode(header); err != nil {
+		return nil, err
+	}
+	return recorder, nil
+}
+
+//Record : Writes an event. Once a write fails every later Record returns that error
+func (recorder *InputRecorder) Record(ev InputEvent) error {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.err == nil {
+		recorder.err = recorder.encoder.Encode(ev)
+	}
+	return recorder.err
+}
+
+//Close : Closes the underlying writer if it can be closed
+func (recorder *InputRecorder) Close() error {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.closer != nil {
+		return recorder.closer.Close()
+	}
+	return nil
+}
+
+//InputReplay : Recorded input handed back out frame by frame
+type InputReplay struct {
+	Header InputRecordingHeader
+	Events []InputEvent
+	cursor int
+}
+
+//ReadInputReplay : Reads a recording written by an InputRecorder
+func ReadInputReplay(r io.Reader) (*InputReplay, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	var replay InputReplay
+	if err := decoder.Decode(&replay.Header); err != nil {
+		return nil, fmt.Errorf("Input recording has no header: %v", err)
+	}
+	for decoder.More() {
+		var ev InputEvent
+		if err := decoder.Decode(&ev); err != nil {
+			return nil, err
+		}
+		replay.Events = append(replay.Events, ev)
+	}
+	return &replay, nil
+}
+
+//LoadInputReplay : Reads a recording from a file
+func LoadInputReplay(path string) (*InputReplay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadInputReplay(file)
+}
+
+//Done : Every event has been replayed
+func (replay *InputReplay) Done() bool {
+	return replay.cursor >= len(replay.Events)
+}
+
+//due : Events recorded up to and including frame that haven't been replayed
+func (replay *InputReplay) due(frame uint64) []InputEvent {
+	start := replay.cursor
+	for replay.cursor < len(replay.Events) && replay.Events[replay.cursor].Frame <= frame {
+		replay.cursor++
+	}
+	return replay.Events[start:replay.cursor]
+}
+
+//RecordInput : Records every input the game dispatches to a file
+func (g *Game) RecordInput(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	recorder, err := NewInputRecorder(file, InputRecordingHeader{
+		Seed:     g.seed,
+		TimeStep: g.timeStep,
+	})
+	if err != nil {
+		file.Close()
+		return err
+	}
+	g.StopRecording()
+	g.window.inputCollection.SetRecorder(recorder)
+	return nil
+}
+
+//StopRecording : Stops recording input and closes the recording
+func (g *Game) StopRecording() error {
+	collection := g.window.inputCollection
+	collection.mu.Lock()
+	recorder := collection.recorder
+	collection.recorder = nil
+	collection.mu.Unlock()
+	if recorder == nil {
+		return nil
+	}
+	return recorder.Close()
+}
+
+//ReplayInput : Replaces live input with a recording and reseeds Rand with the
+//recorded seed. Call before Start to reproduce a run from its beginning
+func (g *Game) ReplayInput(replay *InputReplay) error {
+	if replay.Header.TimeStep != 0 && replay.Header.TimeStep != g.timeStep {
+		return fmt.Errorf("Input recording used a time step of %v, game uses %v", replay.Header.TimeStep, g.timeStep)
+	}
+	g.Seed(replay.Header.Seed)
+	g.window.inputCollection.SetReplay(replay)
+	return nil
+}
@@ -0,0 +1,102 @@
+package goldengine
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//newInputLogGame : Test game that logs the frame, key and a random number of
+//every key it handles
+func newInputLogGame(t *testing.T, backend *HeadlessBackend, log *[]string) *Game {
+	t.Helper()
+	game := newTestGame(t, GameConfig{TimeStep: 10 * time.Millisecond}, backend, map[string]string{
+		"scenes/main.scene": `{"Name":"main"}`,
+	})
+	game.ChangeScene("main")
+	handler := GenInputHandler()
+	for _, code := range []sf.KeyCode{sf.KeyA, sf.KeyB} {
+		code := code
+		handler.RegisterKeyPressedCommand(code, func() {
+			*log = append(*log, fmt.Sprintf("%d pressed %d %d", game.Frame(), code, game.Rand().Intn(1000)))
+		})
+		handler.RegisterKeyReleasedCommand(code, func() {
+			*log = append(*log, fmt.Sprintf("%d released %d", game.Frame(), code))
+		})
+	}
+	set := GenKeyboardSet()
+	set.AddHandler(handler)
+	game.GetWindow().GetInputCollection().InstallKeyboardSet(set)
+	return game
+}
+
+func TestInputRecordReplayRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.jsonl")
+	var recorded []string
+	backend := NewHeadlessBackend(800, 600)
+	game := newInputLogGame(t, backend, &recorded)
+	game.Seed(42)
+	if err := game.RecordInput(path); err != nil {
+		t.Fatal(err)
+	}
+	game.Start()
+	game.Tick(10 * time.Millisecond)
+	backend.PressKey(sf.KeyA)
+	game.Tick(10 * time.Millisecond)
+	//Input of a frame without a step waits for the next step
+	backend.PressKey(sf.KeyB)
+	game.Tick(5 * time.Millisecond)
+	backend.ReleaseKey(sf.KeyA)
+	game.Tick(25 * time.Millisecond)
+	backend.ReleaseKey(sf.KeyB)
+	game.Tick(10 * time.Millisecond)
+	game.Stop()
+	want := []string{
+		fmt.Sprintf("1 pressed %d ", sf.KeyA),
+		fmt.Sprintf("2 pressed %d ", sf.KeyB),
+		fmt.Sprintf("2 released %d", sf.KeyA),
+		fmt.Sprintf("5 released %d", sf.KeyB),
+	}
+	if len(recorded) != len(want) {
+		t.Fatalf("recorded %v, want %d events", recorded, len(want))
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(recorded[i], prefix) {
+			t.Errorf("event %d is %q, want it to start with %q", i, recorded[i], prefix)
+		}
+	}
+
+	replay, err := LoadInputReplay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.Header.Seed != 42 || replay.Header.TimeStep != 10*time.Millisecond || len(replay.Events) != 4 {
+		t.Fatalf("recording has header %+v and %d events", replay.Header, len(replay.Events))
+	}
+	var replayed []string
+	replayBackend := NewHeadlessBackend(800, 600)
+	replayGame := newInputLogGame(t, replayBackend, &replayed)
+	if err := replayGame.ReplayInput(replay); err != nil {
+		t.Fatal(err)
+	}
+	replayGame.Start()
+	defer replayGame.Stop()
+	//Live input is ignored while replaying
+	replayBackend.PressKey(sf.KeyB)
+	for i := 0; i < 6 && !replay.Done(); i++ {
+		replayGame.Tick(10 * time.Millisecond)
+	}
+	if !reflect.DeepEqual(replayed, recorded) {
+		t.Errorf("replay handled\n%v\nrecording handled\n%v", replayed, recorded)
+	}
+
+	other := newTestGame(t, GameConfig{TimeStep: 5 * time.Millisecond}, nil, nil)
+	if err := other.ReplayInput(replay); err == nil {
+		t.Error("replayed a recording made with another time step")
+	}
+}
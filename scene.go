@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io/ioutil"
 	"sort"
+	"sync"
 	"time"
 
 	sf "github.com/manyminds/gosfml"
@@ -14,12 +16,22 @@ import (
 //SceneDefEntity : Defines an Entity within the scene
 type SceneDefEntity struct {
 	Name               string
-	Parent             string
+	Parent             string `json:",omitempty"`
 	Prefab             string
-	TransformArguments map[string]interface{}
+	TransformArguments map[string]interface{} `json:",omitempty"`
 	Position           Vector
 	Scale              Vector
-	Rotation           float32
+	Rotation           float32 `json:",omitempty"`
+	//Disabled : Entity and its children start disabled
+	Disabled bool `json:",omitempty"`
+	//ComponentArguments : Arguments merged into the prefab's components, by component name
+	ComponentArguments map[string]map[string]interface{} `json:",omitempty"`
+	//ColliderArguments : Arguments merged into the prefab's collider
+	ColliderArguments map[string]interface{} `json:",omitempty"`
+	//Tags : Tags added to the prefab's tags
+	Tags []string `json:",omitempty"`
+	//Layer : The prefab's layer when empty
+	Layer string `json:",omitempty"`
 }
 
 //EntityFromSceneDefEntity : Creates an Entity from a scene definition entity
@@ -34,14 +46,44 @@ func EntityFromSceneDefEntity(def SceneDefEntity) (*Entity, error) {
 	if !ok {
 		return nil, fmt.Errorf("Cannot find Prefab with the name: %s", def.Prefab)
 	}
-	for k, v := range def.TransformArguments {
-		prefab.Transformer.Arguments[k] = v
+	prefab.Transformer.Arguments = mergeArguments(prefab.Transformer.Arguments, def.TransformArguments)
+	prefab.Collider.Arguments = mergeArguments(prefab.Collider.Arguments, def.ColliderArguments)
+	for name, args := range def.ComponentArguments {
+		found := false
+		for i := range prefab.Components {
+			if prefab.Components[i].Name == name {
+				prefab.Components[i].Arguments = mergeArguments(prefab.Components[i].Arguments, args)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s: Prefab %s has no component %s", def.Name, def.Prefab, name)
+		}
+	}
+	prefab.Tags = append(prefab.Tags, def.Tags...)
+	if def.Layer != "" {
+		prefab.Layer = def.Layer
+	}
+	entity, err := EntityFromEntityPrefab(prefab)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", def.Name, err)
 	}
-	entity := EntityFromEntityPrefab(prefab)
 	entity.Name = def.Name
 	return entity, nil
 }
 
+//applySceneDefTransform : Places an entity where its scene definition says
+func applySceneDefTransform(entity *Entity, def SceneDefEntity) {
+	if entity.Transfrom != nil {
+		entity.SetPosition(def.Position.ToSFML())
+		if def.Scale != ZeroVector {
+			entity.SetScale(def.Scale.ToSFML())
+		}
+		entity.SetRotation(def.Rotation)
+	}
+	entity.disabled = def.Disabled
+}
+
 //SceneDef : Informaiton to Create a scene from a JSON Template
 type SceneDef struct {
 	Name     string
@@ -53,7 +95,7 @@ func ParseSceneDef(g *Game, sceneName, sceneString string) (*SceneDef, error) {
 	//SceneFuncMap : Functions that can be used in a Scene Def template
 	var SceneFuncMap = template.FuncMap{
 		"gameHeight": func() float32 {
-			pixelHeight := float32(g.GetWindow().renderWindow.GetSize().Y)
+			pixelHeight := float32(g.GetWindow().backend.GetSize().Y)
 			unit := pixelHeight / scale
 			return unit
 		},
@@ -99,6 +141,8 @@ func SceneFromSceneDef(def *SceneDef) (*Scene, error) {
 		entityMap:     entityMap,
 		entityNodeMap: entityNodeMap,
 		entityDefMap:  entityDefMap,
+		timeScale:     1,
+		scheduler:     NewScheduler(),
 	}
 	entityNodeMap[RootNodeName] = scene.root
 	scene.root.scene = &scene
@@ -115,6 +159,7 @@ func SceneFromSceneDef(def *SceneDef) (*Scene, error) {
 			return nil, err
 		}
 		entity.scene = &scene
+		scene.scheduleWaiting(entity)
 		entityMap[entity.id] = entity
 		node := entityNode{
 			scene:  &scene,
@@ -137,7 +182,7 @@ func SceneFromSceneDef(def *SceneDef) (*Scene, error) {
 				parentNode = entityNodeMap[RootNodeName]
 			}
 			if parentNode.children == nil {
-				parentNode.children = make([]*entityNode, 1)
+				parentNode.children = make([]*entityNode, 0)
 			}
 			parentNode.children = append(parentNode.children, node)
 			node.parent = parentNode
@@ -147,22 +192,91 @@ func SceneFromSceneDef(def *SceneDef) (*Scene, error) {
 				if parent.children == nil {
 					parent.children = make(map[uint32]*Entity)
 				}
-				parent.children[entity.id] = parent
+				parent.children[entity.id] = entity
 				entity.parent = parent
 			}
 		}
 		//Set Transform Properties
-		entity.Transfrom.SetPosition(entityDef.Position.ToSFML())
-		if entityDef.Scale != ZeroVector {
-			entity.Transfrom.SetScale(entityDef.Scale.ToSFML())
-		}
-		entity.Transfrom.SetRotation(entityDef.Rotation)
+		applySceneDefTransform(entity, entityDef)
 	}
 	//Scene Operations
 
 	return &scene, nil
 }
 
+//SceneToSceneDef : Describes the current state of a scene in the format
+//ParseSceneDef reads. Entities that weren't made from a prefab are skipped
+func SceneToSceneDef(s *Scene) *SceneDef {
+	def := &SceneDef{
+		Name:     s.Name,
+		Entities: make([]SceneDefEntity, 0, len(s.entityMap)),
+	}
+	var visit func(node *entityNode)
+	visit = func(node *entityNode) {
+		for _, child := range node.children {
+			if child == nil || child.entity == nil {
+				continue
+			}
+			if entityDef, ok := s.entityToSceneDefEntity(child); ok {
+				def.Entities = append(def.Entities, entityDef)
+			}
+			visit(child)
+		}
+	}
+	visit(s.root)
+	return def
+}
+
+//entityToSceneDefEntity : Describes an entity. False if it has no prefab
+func (s *Scene) entityToSceneDefEntity(node *entityNode) (SceneDefEntity, bool) {
+	e := node.entity
+	entityDef, fromDef := s.entityDefMap[e.id]
+	if !fromDef {
+		entityDef.Prefab = e.prefab
+	}
+	if entityDef.Prefab == "" {
+		return entityDef, false
+	}
+	entityDef.Name = e.Name
+	entityDef.Parent = ""
+	if node.parent != nil && node.parent != s.root && node.parent.entity != nil {
+		entityDef.Parent = node.parent.entity.Name
+	}
+	if e.Transfrom != nil {
+		entityDef.Position = Vector2fToVector(e.Transfrom.GetPosition())
+		entityDef.Scale = ZeroVector
+		if scale := e.Transfrom.GetScale(); scale != (sf.Vector2f{X: 1, Y: 1}) {
+			entityDef.Scale = Vector2fToVector(scale)
+		}
+		entityDef.Rotation = e.Transfrom.GetRotation()
+	}
+	entityDef.Disabled = e.disabled
+	entityDef.Tags, entityDef.Layer = nil, ""
+	prefab, _ := PrefabRegister.Get(entityDef.Prefab)
+	for _, tag := range e.tags {
+		if !containsString(prefab.Tags, tag) {
+			entityDef.Tags = append(entityDef.Tags, tag)
+		}
+	}
+	prefabLayer := prefab.Layer
+	if prefabLayer == "" {
+		prefabLayer = DefaultLayer
+	}
+	if e.Layer() != prefabLayer {
+		entityDef.Layer = e.Layer()
+	}
+	return entityDef, true
+}
+
+//Save : Writes the scene to a JSON file that LoadSceneFromFile can read
+func (s *Scene) Save(path string) error {
+	dat, err := json.MarshalIndent(SceneToSceneDef(s), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(dat, '\n'), 0644)
+}
+
 type entityNode struct {
 	scene    *Scene
 	parent   *entityNode
@@ -176,6 +290,18 @@ func (node *entityNode) AddChild(child *entityNode) {
 		node.children = make([]*entityNode, 0)
 	}
 	node.children = append(node.children, child)
+	child.parent = node
+}
+
+//removeChild : Detaches a child node
+func (node *entityNode) removeChild(child *entityNode) {
+	for i, c := range node.children {
+		if c == child {
+			node.children = append(node.children[:i], node.children[i+1:]...)
+			break
+		}
+	}
+	child.parent = nil
 }
 
 func (node *entityNode) Start() {
@@ -198,7 +324,9 @@ func (node *entityNode) Awake() {
 		return
 	}
 	if node.entity != nil {
-
+		if node.entity.disabled {
+			return
+		}
 		node.entity.Awake()
 	}
 	fmt.Println("Waking Up entitynode	")
@@ -208,27 +336,39 @@ func (node *entityNode) Awake() {
 	}
 }
 
-func (node *entityNode) Update(dur time.Duration) {
+//Sleep : Sleeps children before their parent
+func (node *entityNode) Sleep() {
 	if node == nil {
 		return
 	}
+	for _, child := range node.children {
+		child.Sleep()
+	}
 	if node.entity != nil {
+		node.entity.Sleep()
+	}
+}
 
-		node.entity.Update(dur)
+//Stop : Stops children before their parent
+func (node *entityNode) Stop() {
+	if node == nil {
+		return
 	}
 	for _, child := range node.children {
-		c := child
-		c.Update(dur)
+		child.Stop()
+	}
+	if node.entity != nil {
+		node.entity.Stop()
 	}
 }
 
+//Draw : Draws the node and its children in the node order, each relative to its parent
 func (node *entityNode) Draw(target sf.RenderTarget, renderStates sf.RenderStates) {
+	if node.entity != nil && node.entity.disabled {
+		return
+	}
 	if node.entity != nil && node.entity.Transfrom != nil {
-		// transform := node.entity.Transfrom.GetTransform()
-		// combinedTransform := renderStates.Transform.Combine(&transform)
-		// renderStates.Transform = *combinedTransform
-		//fmt.Println(node.entity.Transfrom.GetPosition())
-		target.Draw(node.entity.Transfrom, renderStates)
+		target.Draw(node.entity.Transfrom, node.entity.worldRenderStates(renderStates))
 	}
 
 	for _, child := range node.children {
@@ -245,6 +385,15 @@ const SceneChangedMSG = MessageType("SceneChanged")
 //SceneAddedEntityMSG : Added Entity to scene. Sends pointer to entity
 const SceneAddedEntityMSG = MessageType("SceneAddedEntity")
 
+//SceneEnabledEntityMSG : Entity of the scene was enabled, or its parent was. Sends pointer to entity
+const SceneEnabledEntityMSG = MessageType("SceneEnabledEntity")
+
+//SceneDisabledEntityMSG : Entity of the scene was disabled, or its parent was. Sends pointer to entity
+const SceneDisabledEntityMSG = MessageType("SceneDisabledEntity")
+
+//SceneRemovedEntityMSG : Removed Entity from scene at the end of a frame. Sends pointer to entity
+const SceneRemovedEntityMSG = MessageType("SceneRemovedEntity")
+
 //Scene : Everything that is being rendered
 type Scene struct {
 	Name          string
@@ -253,10 +402,47 @@ type Scene struct {
 	entityMap     map[uint32]*Entity
 	entityNodeMap map[string]*entityNode
 	entityDefMap  map[uint32]SceneDefEntity
+	alpha         float32
+	timeScale     float64
+	started       bool
+	woken         bool
+	scheduler     *Scheduler
+	//Entities destroyed this frame, removed by flushDestroyed
+	destroyed   []*Entity
+	destroyLock sync.Mutex
+	//Grid of entity bounds. Entities that moved are queued and put in new
+	//cells by the next query, every entity when spatialDirty
+	spatial      *spatialHash
+	spatialDirty bool
+	moved        []*Entity
+	movedLock    sync.Mutex
+	//Tree and update order, rebuilt when entities or components change.
+	//orderLock guards orderValid, which parallel components clear
+	orderValid    bool
+	orderLock     sync.Mutex
+	orderVersion  uint64
+	nodes         []*entityNode
+	componentList []componentSlot
+	orderBuilds   uint64
+	//Parallel updates, see SetUpdateWorkers
+	updateWorkers int
+	plan          updatePlan
+	pool          *updatePool
+	//Entities by tag, nil until FindByTag first needs it
+	tags    map[string]map[uint32]*Entity
+	tagLock sync.Mutex
+	//Data oriented entities, nil until World is first called
+	world     *World
+	drawables []worldDrawable
+	//Systems added to the scene and the order they run in with the Game's
+	systems     systemList
+	systemOrder systemOrder
 
 	Awake  func()
 	Start  func()
 	Update func(time.Duration)
+	Sleep  func()
+	Stop   func()
 
 	BasicMailBox
 }
@@ -267,11 +453,19 @@ func (s *Scene) AddEntity(e *Entity) {
 		return
 	}
 	node := &entityNode{
+		scene:  s,
 		entity: e,
 	}
-	s.root.children = append(s.root.children, node)
+	e.scene = s
+	s.scheduleWaiting(e)
 	s.entityNodeMap[e.Name] = node
 	s.entityMap[e.id] = e
+	s.spatialMoved(e)
+	s.invalidateOrder()
+	e.destroyed = false
+	for _, tag := range e.tags {
+		s.indexTag(e, tag)
+	}
 	if e.parent != nil {
 		parent, ok := s.entityNodeMap[e.parent.Name]
 		if ok {
@@ -291,7 +485,142 @@ func (s *Scene) AddEntity(e *Entity) {
 	})
 }
 
-//GetEntities : Gets all entities in scene
+//InstantiateOptions : Where to put an entity made with Scene.Instantiate and
+//how it differs from its prefab
+type InstantiateOptions struct {
+	//Name : Unique name in the scene. The prefab name and the entity id when empty
+	Name     string
+	Position Vector
+	//Scale : The prefab's scale when zero
+	Scale    Vector
+	Rotation float32
+	//Parent : Entity of the scene to attach to. Position is relative to it
+	Parent *Entity
+	//Disabled : Entity starts disabled
+	Disabled           bool
+	TransformArguments map[string]interface{}
+	//ComponentArguments : Arguments merged into the prefab's components, by component name
+	ComponentArguments map[string]map[string]interface{}
+	ColliderArguments  map[string]interface{}
+	//Tags : Tags added to the prefab's tags
+	Tags []string
+	//Layer : The prefab's layer when empty
+	Layer string
+}
+
+//Instantiate : Creates an entity from a prefab and adds it to the scene. It
+//gets a physics body if the scene has a space, and is started and woken up
+//if the scene is. The prefab itself is never changed by the overrides
+func (s *Scene) Instantiate(prefabName string, options InstantiateOptions) (entity *Entity, err error) {
+	def := SceneDefEntity{
+		Name:               options.Name,
+		Prefab:             prefabName,
+		Position:           options.Position,
+		Scale:              options.Scale,
+		Rotation:           options.Rotation,
+		Disabled:           options.Disabled,
+		TransformArguments: copyArguments(options.TransformArguments),
+		ColliderArguments:  copyArguments(options.ColliderArguments),
+		Tags:               append([]string(nil), options.Tags...),
+		Layer:              options.Layer,
+	}
+	if options.ComponentArguments != nil {
+		def.ComponentArguments = make(map[string]map[string]interface{}, len(options.ComponentArguments))
+		for name, args := range options.ComponentArguments {
+			def.ComponentArguments[name] = copyArguments(args)
+		}
+	}
+	if def.Name == "" {
+		def.Name = prefabName
+	} else if _, ok := s.entityNodeMap[def.Name]; ok {
+		return nil, fmt.Errorf("Scene %s already has an entity named %s", s.Name, def.Name)
+	}
+	if parent := options.Parent; parent != nil {
+		if parent.scene != s || parent.destroyed {
+			return nil, fmt.Errorf("Parent %s of %s isn't in scene %s", parent.Name, def.Name, s.Name)
+		}
+		def.Parent = parent.Name
+	}
+	entity, err = EntityFromSceneDefEntity(def)
+	if err != nil {
+		return nil, err
+	}
+	if options.Name == "" {
+		entity.Name = fmt.Sprintf("%s%d", prefabName, entity.id)
+		def.Name = entity.Name
+	}
+	applySceneDefTransform(entity, def)
+	if options.Parent != nil {
+		options.Parent.AddChild(entity)
+	}
+	s.entityDefMap[entity.id] = def
+	s.AddEntity(entity)
+	if s.started {
+		entity.Start()
+	}
+	if s.woken && entity.ActiveInHierarchy() {
+		entity.Awake()
+	}
+	return entity, nil
+}
+
+//RemoveEntity : Destroys an entity of the scene and its children. They are
+//removed at the end of the frame
+func (s *Scene) RemoveEntity(e *Entity) {
+	if e.scene != s {
+		return
+	}
+	e.Destroy()
+}
+
+//flushDestroyed : Removes the entities destroyed during the frame
+func (s *Scene) flushDestroyed() {
+	for len(s.destroyed) > 0 {
+		destroyed := s.destroyed
+		s.destroyed = nil
+		for _, e := range destroyed {
+			s.removeEntity(e)
+		}
+	}
+}
+
+//removeEntity : Stops an entity and takes it and its children out of the scene,
+//children first
+func (s *Scene) removeEntity(e *Entity) {
+	if _, ok := s.entityMap[e.id]; !ok {
+		return
+	}
+	for _, child := range e.children {
+		s.removeEntity(child)
+	}
+	e.Stop()
+	if node, ok := s.entityNodeMap[e.Name]; ok && node.entity == e {
+		if node.parent != nil {
+			node.parent.removeChild(node)
+		}
+		delete(s.entityNodeMap, e.Name)
+	}
+	delete(s.entityMap, e.id)
+	delete(s.entityDefMap, e.id)
+	for _, tag := range e.tags {
+		s.unindexTag(e, tag)
+	}
+	s.unlink(e)
+	if s.spatial != nil {
+		s.spatial.remove(e.id)
+	}
+	s.invalidateOrder()
+	if e.parent != nil && !e.parent.destroyed {
+		e.parent.RemoveChild(e)
+	}
+	s.PostMessage(Message{
+		Message: SceneRemovedEntityMSG,
+		Content: e,
+	})
+	e.scene = nil
+}
+
+//GetEntities : Gets all entities in scene in the order they were created
 func (s *Scene) GetEntities() []*Entity {
 	list := make([]*Entity, len(s.entityMap))
 	counter := 0
@@ -299,9 +628,39 @@ func (s *Scene) GetEntities() []*Entity {
 		list[counter] = e
 		counter = counter + 1
 	}
+	sortEntities(list)
 	return list
 }
 
+//Alpha : How far the game is between the last fixed update and the next one,
+//from 0 to 1. Use it to smooth drawing
+func (s *Scene) Alpha() float32 {
+	return s.alpha
+}
+
+//Scheduler : Timers and sequences run by this scene
+func (s *Scene) Scheduler() *Scheduler {
+	return s.scheduler
+}
+
+//TimeScale : How fast time passes in this scene compared to the game
+func (s *Scene) TimeScale() float64 {
+	return s.timeScale
+}
+
+//SetTimeScale : Scales the durations the scene's components and physics get.
+//0.5 is slow motion, 2 is fast forward and 0 freezes the scene
+func (s *Scene) SetTimeScale(scale float64) {
+	if scale < 0 {
+		scale = 0
+	}
+	s.timeScale = scale
+}
+
+func (s *Scene) scaleDuration(dur time.Duration) time.Duration {
+	return time.Duration(float64(dur) * s.timeScale)
+}
+
 //RecalculateScale : Changes the of every entity
 func (s *Scene) RecalculateScale() {
 	for _, e := range s.entityMap {
@@ -312,7 +671,10 @@ func (s *Scene) RecalculateScale() {
 //GetEntityByName : Returns an entity in the scene with that name
 func (s *Scene) GetEntityByName(name string) (*Entity, bool) {
 	node, found := s.entityNodeMap[name]
-	return node.entity, found
+	if !found || node == s.root {
+		return nil, false
+	}
+	return node.entity, true
 }
 
 //SetZIndex : SetsZIndex of a node
@@ -325,23 +687,40 @@ func (s *Scene) SetZIndex(name string, index int) {
 
 //Draw : Draws the scene to a render target
 func (s *Scene) Draw(target sf.RenderTarget, renderStates sf.RenderStates) {
-	entities := make([]*entityNode, len(s.entityNodeMap))
-	counter := 0
-	for _, e := range s.entityNodeMap {
-		entities[counter] = e
-		counter = counter + 1
-	}
-	sort.Sort(byZIndex(entities))
-	for _, e := range entities {
-		if e.entity != nil {
+	s.Render(target, renderStates)
+}
 
-			target.Draw(e.entity.Transfrom, renderStates)
+//Render : Draws every entity of the scene onto a Renderer. Children are drawn
+//relative to their parent. Entities with the same z index are drawn in tree
+//order, parents before children. Renderables of the scene's World are drawn
+//after the entities of their layer and z index
+func (s *Scene) Render(target Renderer, renderStates sf.RenderStates) {
+	profiler := s.profiler()
+	start := profiler.now()
+	defer profiler.end("render", "Scene "+s.Name, start, profileRenderThread)
+	entities := s.visibleNodes(target, renderStates)
+	sort.Stable(byZIndex(entities))
+	drawables := s.worldDrawables()
+	next := 0
+	for _, e := range entities {
+		for ; next < len(drawables) && drawables[next].before(e); next++ {
+			drawables[next].draw(target, renderStates)
+		}
+		if e.entity != nil && e.entity.Transfrom != nil {
+			target.Draw(e.entity.Transfrom, e.entity.worldRenderStates(renderStates))
 		}
 	}
+	for ; next < len(drawables); next++ {
+		drawables[next].draw(target, renderStates)
+	}
 }
 
 //Start : Starts all entities in the scene
 func (s *Scene) start() {
+	if s.started {
+		return
+	}
+	s.started = true
 	if s.Start != nil {
 		s.Start()
 	}
@@ -350,18 +729,63 @@ func (s *Scene) start() {
 
 //Awake : Wakes up all entities in the scene
 func (s *Scene) awake() {
+	if s.woken {
+		return
+	}
+	s.woken = true
 	if s.Awake != nil {
 		s.Awake()
 	}
 	s.root.Awake()
 }
 
-//Update : Updates all entities in node
+//sleep : Sleeps all entities in the scene, children first
+func (s *Scene) sleep() {
+	if !s.woken {
+		return
+	}
+	s.woken = false
+	s.root.Sleep()
+	if s.Sleep != nil {
+		s.Sleep()
+	}
+}
+
+//stop : Sleeps then stops all entities in the scene, children first
+func (s *Scene) stop() {
+	s.sleep()
+	s.stopUpdatePool()
+	if s.started {
+		s.started = false
+		s.root.Stop()
+		if s.Stop != nil {
+			s.Stop()
+		}
+	}
+}
+
+//update : Updates the components of awake entities. Components with a lower
+//priority update first, ties update in tree order
 func (s *Scene) update(dur time.Duration) {
 	if s.Update != nil {
 		s.Update(dur)
 	}
-	s.root.Update(dur)
+	profiler := s.profiler()
+	if s.UpdateWorkers() > 1 {
+		s.updateParallel(dur, profiler)
+	} else {
+		s.updateSlots(s.updateOrder(), dur, profiler, nil)
+	}
+	s.updateWorld(dur)
+	s.scheduler.update(dur)
+}
+
+//interpolate : Lets every entity smooth its drawing
+func (s *Scene) interpolate(alpha float32) {
+	s.alpha = alpha
+	for _, e := range s.entityMap {
+		e.Interpolate(alpha)
+	}
 }
 
 //RecieveMessage : Handles Message
@@ -380,6 +804,19 @@ func (s byZIndex) Len() int {
 func (s byZIndex) Swap(i, j int) {
 	s[i], s[j] = s[j], s[i]
 }
+
+//Less : Lower layers first, then lower z indexes
 func (s byZIndex) Less(i, j int) bool {
+	li, lj := s[i].layerOrder(), s[j].layerOrder()
+	if li != lj {
+		return li < lj
+	}
 	return s[i].zIndex < s[j].zIndex
 }
+
+func (node *entityNode) layerOrder() int {
+	if node.entity == nil {
+		return 0
+	}
+	return LayerRegister.DrawOrder(node.entity.layer)
+}
@@ -0,0 +1,82 @@
+package goldengine
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+func TestSceneSaveLoadRoundTrip(t *testing.T) {
+	game := newTestGame(t, GameConfig{}, nil, map[string]string{
+		"prefabs/crate.json": `{"Name":"crate","Tags":["crate"],"Transformer":{"Kind":"RectangleShape","Arguments":{}}}`,
+		"scenes/main.scene": `{"Name":"main","Entities":[
+			{"Name":"box","Prefab":"crate","Position":{"X":10,"Y":20},"Scale":{"X":2,"Y":2},"Rotation":45},
+			{"Name":"lid","Prefab":"crate","Parent":"box","Disabled":true,"Tags":["loot"],"Layer":"pickups"}
+		]}`,
+	})
+	game.ChangeScene("main")
+	game.Start()
+	defer game.Stop()
+	scene := game.GetCurrentScene()
+	box, _ := scene.GetEntityByName("box")
+	box.Move(sf.Vector2f{X: 5, Y: 0})
+	box.AddTag("moved")
+	if _, err := scene.Instantiate("crate", InstantiateOptions{
+		Name:     "extra",
+		Position: Vector{X: 3, Y: 4},
+		Parent:   box,
+		Layer:    "pickups",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	free := NewEntity()
+	free.Name = "free"
+	scene.AddEntity(free)
+
+	path := filepath.Join(t.TempDir(), "saved.scene")
+	if err := scene.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := game.LoadSceneFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, reloaded := SceneToSceneDef(scene), SceneToSceneDef(loaded)
+	if !reflect.DeepEqual(saved, reloaded) {
+		t.Errorf("loaded scene describes itself as\n%+v\nsaved scene as\n%+v", reloaded, saved)
+	}
+	if names := entityNames(loaded.GetEntities()); !reflect.DeepEqual(names, []string{"box", "lid", "extra"}) {
+		t.Fatalf("loaded entities %v, want box lid extra without the entity that has no prefab", names)
+	}
+
+	//Scene definitions are in game units, transformers in pixels
+	units := func(x, y float32) sf.Vector2f {
+		v := Vector{X: x, Y: y}
+		return v.ToSFML()
+	}
+	loadedBox, _ := loaded.GetEntityByName("box")
+	want := units(10, 20)
+	want.X += 5
+	if pos := loadedBox.Transfrom.GetPosition(); pos != want {
+		t.Errorf("box at %v, want %v", pos, want)
+	}
+	if scale, rotation := loadedBox.Transfrom.GetScale(), loadedBox.Transfrom.GetRotation(); scale != units(2, 2) || rotation != 45 {
+		t.Errorf("box scale %v rotation %v, want %v and 45", scale, rotation, units(2, 2))
+	}
+	if !reflect.DeepEqual(loadedBox.Tags(), []string{"crate", "moved"}) {
+		t.Errorf("box tags %v, want crate moved", loadedBox.Tags())
+	}
+	lid, _ := loaded.GetEntityByName("lid")
+	if lid.parent != loadedBox || lid.IsEnabled() || lid.Layer() != "pickups" || !lid.HasTag("loot") {
+		t.Errorf("lid parent %v, enabled %v, layer %s, tags %v", lid.parent, lid.IsEnabled(), lid.Layer(), lid.Tags())
+	}
+	extra, _ := loaded.GetEntityByName("extra")
+	if extra.parent != loadedBox || extra.Layer() != "pickups" {
+		t.Errorf("instantiated entity has parent %v and layer %s", extra.parent, extra.Layer())
+	}
+	if pos := extra.Transfrom.GetPosition(); pos != units(3, 4) {
+		t.Errorf("instantiated entity at %v, want %v", pos, units(3, 4))
+	}
+}
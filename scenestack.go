@@ -0,0 +1,178 @@
+package goldengine
+
+import (
+	"time"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//ScenePushedMSG : Scene was pushed over the current ones. Sends pointer to SceneLayer
+const ScenePushedMSG = MessageType("ScenePushed")
+
+//ScenePoppedMSG : Top scene was popped. Sends pointer to the popped scene
+const ScenePoppedMSG = MessageType("ScenePopped")
+
+//SceneLayer : A scene in the scene stack and how it treats the scenes below it
+type SceneLayer struct {
+	Scene *Scene
+	//UpdateBelow : Scenes below keep updating. Scenes that stop updating are put to sleep
+	UpdateBelow bool
+	//DrawBelow : Scenes below keep drawing
+	DrawBelow bool
+	//Transition : Drawn when this layer is pushed and popped. nil cuts
+	Transition Transition
+}
+
+//sceneStack : Scenes from the bottom to the top
+type sceneStack []*SceneLayer
+
+//top : Scene on top of the stack
+func (stack sceneStack) top() *Scene {
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1].Scene
+}
+
+//updating : Scenes that update, from the bottom to the top
+func (stack sceneStack) updating() []*Scene {
+	return stack.visible(func(layer *SceneLayer) bool {
+		return layer.UpdateBelow
+	})
+}
+
+//drawing : Scenes that draw, from the bottom to the top
+func (stack sceneStack) drawing() []*Scene {
+	return stack.visible(func(layer *SceneLayer) bool {
+		return layer.DrawBelow
+	})
+}
+
+func (stack sceneStack) visible(passes func(*SceneLayer) bool) []*Scene {
+	bottom := len(stack) - 1
+	for bottom > 0 && passes(stack[bottom]) {
+		bottom--
+	}
+	if bottom < 0 {
+		return nil
+	}
+	scenes := make([]*Scene, 0, len(stack)-bottom)
+	for _, layer := range stack[bottom:] {
+		scenes = append(scenes, layer.Scene)
+	}
+	return scenes
+}
+
+//contains : Whether a scene is anywhere in the stack
+func (stack sceneStack) contains(s *Scene) bool {
+	for _, layer := range stack {
+		if layer.Scene == s {
+			return true
+		}
+	}
+	return false
+}
+
+//draw : Draws every drawing scene, the bottom one first
+func (stack sceneStack) draw(target Renderer, renderStates sf.RenderStates) {
+	for _, scene := range stack.drawing() {
+		scene.Render(target, renderStates)
+	}
+}
+
+//Transition : Effect drawn while the game goes from one scene to another
+type Transition interface {
+	Duration() time.Duration
+	Draw(frame TransitionFrame)
+}
+
+//TransitionFrame : Everything a Transition needs to draw one frame
+type TransitionFrame struct {
+	Target Renderer
+	Size   sf.Vector2u
+	//Progress : From 0 when the transition starts to 1 when it ends
+	Progress float32
+	//DrawFrom : Draws the scenes before the change
+	DrawFrom func(target Renderer, renderStates sf.RenderStates)
+	//DrawTo : Draws the scenes after the change
+	DrawTo func(target Renderer, renderStates sf.RenderStates)
+}
+
+//CutTransition : Changes scenes instantly
+type CutTransition struct{}
+
+//Duration : Always 0
+func (CutTransition) Duration() time.Duration {
+	return 0
+}
+
+//Draw : Draws the new scenes
+func (CutTransition) Draw(frame TransitionFrame) {
+	frame.DrawTo(frame.Target, sf.DefaultRenderStates())
+}
+
+//FadeTransition : Fades the old scenes out to Color then fades the new scenes in
+type FadeTransition struct {
+	Color  sf.Color
+	Length time.Duration
+	cover  *sf.RectangleShape
+}
+
+//Duration : Length of the fade
+func (t *FadeTransition) Duration() time.Duration {
+	return t.Length
+}
+
+//Draw : Draws the old scenes for the first half and the new ones for the second, under a cover of Color
+func (t *FadeTransition) Draw(frame TransitionFrame) {
+	opacity := frame.Progress * 2
+	if frame.Progress < 0.5 {
+		frame.DrawFrom(frame.Target, sf.DefaultRenderStates())
+	} else {
+		frame.DrawTo(frame.Target, sf.DefaultRenderStates())
+		opacity = 2 - opacity
+	}
+	if t.cover == nil {
+		cover, err := sf.NewRectangleShape()
+		if err != nil {
+			return
+		}
+		t.cover = cover
+	}
+	color := t.Color
+	color.A = uint8(float32(color.A) * opacity)
+	t.cover.SetSize(sf.Vector2f{X: float32(frame.Size.X), Y: float32(frame.Size.Y)})
+	t.cover.SetFillColor(color)
+	frame.Target.Draw(t.cover, sf.DefaultRenderStates())
+}
+
+//SlideTransition : New scenes push the old ones off screen. Direction is where
+//the old scenes move, for example {X: -1} slides them out to the left
+type SlideTransition struct {
+	Direction sf.Vector2f
+	Length    time.Duration
+}
+
+//Duration : Length of the slide
+func (t SlideTransition) Duration() time.Duration {
+	return t.Length
+}
+
+//Draw : Draws both scenes offset by the progress of the slide
+func (t SlideTransition) Draw(frame TransitionFrame) {
+	width := t.Direction.X * float32(frame.Size.X)
+	height := t.Direction.Y * float32(frame.Size.Y)
+	from := sf.DefaultRenderStates()
+	from.Transform = Matrix{A: 1, C: width * frame.Progress, E: 1, F: height * frame.Progress}.ToSFML()
+	to := sf.DefaultRenderStates()
+	to.Transform = Matrix{A: 1, C: width * (frame.Progress - 1), E: 1, F: height * (frame.Progress - 1)}.ToSFML()
+	frame.DrawFrom(frame.Target, from)
+	frame.DrawTo(frame.Target, to)
+}
+
+//runningTransition : Transition being drawn and what it transitions from
+type runningTransition struct {
+	transition Transition
+	from       sceneStack
+	elapsed    time.Duration
+}
@@ -0,0 +1,95 @@
+package goldengine
+
+import (
+	"testing"
+	"time"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//stackScene : Scene of the stack with one drawn entity counting its updates
+type stackScene struct {
+	scene *Scene
+	shape sf.Drawer
+	life  *testLife
+}
+
+func newStackScene(t *testing.T, game *Game, name string) stackScene {
+	t.Helper()
+	scene, ok := game.GetScene(name)
+	if !ok {
+		t.Fatalf("no scene %s", name)
+	}
+	shape, err := sf.NewRectangleShape()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shape.SetSize(sf.Vector2f{X: 10, Y: 10})
+	e, life := newLifeEntity(name + "Entity")
+	e.Transfrom = shape
+	e.SetPosition(sf.Vector2f{X: 100, Y: 100})
+	scene.AddEntity(e)
+	return stackScene{scene: scene, shape: shape, life: life}
+}
+
+//drawn : Whether the scene's entity was drawn in the last frame
+func (s stackScene) drawn(backend *HeadlessBackend) bool {
+	for _, call := range backend.LastFrame() {
+		if call.Drawer == s.shape {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSceneStackUpdateAndDrawBelow(t *testing.T) {
+	backend := NewHeadlessBackend(800, 600)
+	game := newTestGame(t, GameConfig{TimeStep: 10 * time.Millisecond}, backend, map[string]string{
+		"scenes/main.scene":  `{"Name":"main"}`,
+		"scenes/pause.scene": `{"Name":"pause"}`,
+	})
+	main := newStackScene(t, game, "main")
+	pause := newStackScene(t, game, "pause")
+	game.ChangeScene("main")
+	game.Start()
+	defer game.Stop()
+
+	tick := func(step string, mainUpdates, pauseUpdates int, mainDrawn, pauseDrawn bool) {
+		t.Helper()
+		main.life.updates, pause.life.updates = 0, 0
+		game.Tick(10 * time.Millisecond)
+		if main.life.updates != mainUpdates || pause.life.updates != pauseUpdates {
+			t.Errorf("%s: main updated %d and pause %d times, want %d and %d",
+				step, main.life.updates, pause.life.updates, mainUpdates, pauseUpdates)
+		}
+		if main.drawn(backend) != mainDrawn || pause.drawn(backend) != pauseDrawn {
+			t.Errorf("%s: main drawn %v and pause drawn %v, want %v and %v",
+				step, main.drawn(backend), pause.drawn(backend), mainDrawn, pauseDrawn)
+		}
+	}
+	tick("main alone", 1, 0, true, false)
+
+	game.PushScene("pause", SceneLayer{DrawBelow: true})
+	tick("pause menu over main", 0, 1, true, true)
+	if main.life.sleeps != 1 || game.GetCurrentScene() != pause.scene {
+		t.Errorf("main slept %d times under the pause menu, want 1", main.life.sleeps)
+	}
+
+	game.PopScene()
+	tick("popped", 1, 0, true, false)
+	if main.life.awakes != 2 || pause.life.stops != 1 {
+		t.Errorf("main woke %d times and pause stopped %d times, want 2 and 1", main.life.awakes, pause.life.stops)
+	}
+
+	game.PushScene("pause", SceneLayer{UpdateBelow: true})
+	tick("overlay updating main", 1, 1, false, true)
+	if main.life.sleeps != 1 {
+		t.Errorf("main slept %d times under an overlay that updates it, want 1", main.life.sleeps)
+	}
+
+	game.PushScene("main", SceneLayer{})
+	tick("pushed twice", 1, 1, false, true)
+	game.PopScene()
+	game.PopScene()
+	tick("popped the last scene", 1, 0, true, false)
+}
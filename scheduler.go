@@ -0,0 +1,298 @@
+package goldengine
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+//Scheduler : Runs timers and sequences on the game loop. Every scene has one
+//and advances it with the scene's scaled time, so pausing or slowing the
+//scene pauses or slows its timers
+type Scheduler struct {
+	now     time.Duration
+	timers  []*Timer
+	counter uint64
+	//lock guards timers and counter, which components updating in parallel add to
+	lock sync.Mutex
+}
+
+//Timer : Handle to something scheduled. Cancel stops it
+type Timer struct {
+	id        uint64
+	due       time.Duration
+	interval  time.Duration
+	fn        func()
+	steps     []Step
+	step      int
+	elapsed   time.Duration
+	owner     *Entity
+	cancelled bool
+	done      bool
+	//sequence : Runs steps instead of fn, even when there are none
+	sequence bool
+	//slot : Update slot of the owner's tree when a parallel component scheduled it
+	slot int
+}
+
+//Step : One step of a sequence. Called every update with the time since the
+//step began and returns true once it's finished
+type Step func(elapsed time.Duration) bool
+
+//NewScheduler : Creates an empty Scheduler
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+//After : Calls fn once after d
+func (s *Scheduler) After(d time.Duration, fn func()) *Timer {
+	return s.add(afterTimer(d, fn))
+}
+
+//Every : Calls fn every d until cancelled
+func (s *Scheduler) Every(d time.Duration, fn func()) *Timer {
+	return s.add(everyTimer(d, fn))
+}
+
+//Sequence : Runs steps one after the other, starting on the next update
+func (s *Scheduler) Sequence(steps ...Step) *Timer {
+	return s.add(sequenceTimer(steps))
+}
+
+func afterTimer(d time.Duration, fn func()) *Timer {
+	return &Timer{
+		due: d,
+		fn:  fn,
+	}
+}
+
+func everyTimer(d time.Duration, fn func()) *Timer {
+	if d <= 0 {
+		panic("Every requires a positive interval")
+	}
+	return &Timer{
+		due:      d,
+		interval: d,
+		fn:       fn,
+	}
+}
+
+func sequenceTimer(steps []Step) *Timer {
+	return &Timer{
+		steps:    steps,
+		sequence: true,
+	}
+}
+
+//CancelOwnedBy : Cancels every timer owned by e
+func (s *Scheduler) CancelOwnedBy(e *Entity) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, t := range s.timers {
+		if t.owner == e {
+			t.Cancel()
+		}
+	}
+}
+
+//CancelAll : Cancels every timer
+func (s *Scheduler) CancelAll() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, t := range s.timers {
+		t.Cancel()
+	}
+}
+
+//Now : Scaled time the scheduler has run for
+func (s *Scheduler) Now() time.Duration {
+	return s.now
+}
+
+//add : Schedules a timer whose due time is relative to now
+func (s *Scheduler) add(t *Timer) *Timer {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t.due += s.now
+	s.counter++
+	t.id = s.counter
+	s.timers = append(s.timers, t)
+	return t
+}
+
+//update : Advances the clock by dt and runs everything due, earliest first.
+//Timers added while updating run on a later update
+func (s *Scheduler) update(dt time.Duration) {
+	s.now += dt
+	s.lock.Lock()
+	timers := make([]*Timer, len(s.timers))
+	copy(timers, s.timers)
+	s.lock.Unlock()
+	sort.SliceStable(timers, func(i, j int) bool {
+		if timers[i].due != timers[j].due {
+			return timers[i].due < timers[j].due
+		}
+		return timers[i].id < timers[j].id
+	})
+	for _, t := range timers {
+		if t.cancelled || t.due > s.now {
+			continue
+		}
+		if t.sequence {
+			t.runSteps(dt)
+			continue
+		}
+		for !t.cancelled && t.due <= s.now {
+			t.fn()
+			if t.interval <= 0 {
+				t.done = true
+				break
+			}
+			t.due += t.interval
+		}
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	live := s.timers[:0]
+	for _, t := range s.timers {
+		if !t.cancelled && !t.done {
+			live = append(live, t)
+		}
+	}
+	for i := len(live); i < len(s.timers); i++ {
+		s.timers[i] = nil
+	}
+	s.timers = live
+}
+
+//runSteps : Runs every step that finishes this update
+func (t *Timer) runSteps(dt time.Duration) {
+	t.elapsed += dt
+	for !t.cancelled && t.step < len(t.steps) {
+		if !t.steps[t.step](t.elapsed) {
+			return
+		}
+		t.step++
+		t.elapsed = 0
+	}
+	t.done = true
+}
+
+//Cancel : Stops the timer. It never runs again
+func (t *Timer) Cancel() {
+	t.cancelled = true
+}
+
+//Cancelled : Whether Cancel was called
+func (t *Timer) Cancelled() bool {
+	return t.cancelled
+}
+
+//Done : Whether a one shot timer fired or a sequence finished
+func (t *Timer) Done() bool {
+	return t.done
+}
+
+//OwnedBy : Cancels the timer when e stops
+func (t *Timer) OwnedBy(e *Entity) *Timer {
+	t.owner = e
+	return t
+}
+
+//Wait : Step that finishes after d
+func Wait(d time.Duration) Step {
+	return func(elapsed time.Duration) bool {
+		return elapsed >= d
+	}
+}
+
+//Do : Step that calls fn and finishes
+func Do(fn func()) Step {
+	return func(time.Duration) bool {
+		fn()
+		return true
+	}
+}
+
+//WaitUntil : Step that finishes once cond returns true
+func WaitUntil(cond func() bool) Step {
+	return func(time.Duration) bool {
+		return cond()
+	}
+}
+
+//After : Calls fn once after d on the entity's scene. Cancelled when the entity
+//stops. An entity that isn't in a scene yet waits for one, d counts from when
+//it's added
+func (e *Entity) After(d time.Duration, fn func()) *Timer {
+	return e.schedule(afterTimer(d, fn))
+}
+
+//Every : Calls fn every d on the entity's scene. Cancelled when the entity
+//stops. Waits for a scene like After
+func (e *Entity) Every(d time.Duration, fn func()) *Timer {
+	return e.schedule(everyTimer(d, fn))
+}
+
+//Sequence : Runs steps on the entity's scene. Cancelled when the entity stops.
+//Waits for a scene like After
+func (e *Entity) Sequence(steps ...Step) *Timer {
+	return e.schedule(sequenceTimer(steps))
+}
+
+//schedule : Adds a timer owned by e to its scene's scheduler, or keeps it
+//until e is added to a scene
+func (e *Entity) schedule(t *Timer) *Timer {
+	t.OwnedBy(e)
+	root := e
+	for root.parent != nil {
+		root = root.parent
+	}
+	t.slot = root.updateSlot
+	if e.scene == nil {
+		e.timers = append(e.timers, t)
+		return t
+	}
+	return e.scene.scheduler.add(t)
+}
+
+//cancelTimers : Cancels the timers e owns, scheduled or waiting for a scene
+func (e *Entity) cancelTimers() {
+	if e.scene != nil {
+		e.scene.scheduler.CancelOwnedBy(e)
+	}
+	for _, t := range e.timers {
+		t.Cancel()
+	}
+	e.timers = nil
+}
+
+//scheduleWaiting : Schedules the timers e made before it was in the scene
+func (s *Scene) scheduleWaiting(e *Entity) {
+	for _, t := range e.timers {
+		if !t.cancelled {
+			s.scheduler.add(t)
+		}
+	}
+	e.timers = nil
+}
+
+//sortAdded : Puts the timers added after the first added ones in update slot
+//order and numbers them again, so timers that parallel components scheduled
+//for the same time run in the order they would if every component updated alone
+func (s *Scheduler) sortAdded(added int) {
+	tail := s.timers[added:]
+	sort.SliceStable(tail, func(i, j int) bool {
+		return tail[i].slot < tail[j].slot
+	})
+	ids := make([]uint64, len(tail))
+	for i, t := range tail {
+		ids[i] = t.id
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	for i, t := range tail {
+		t.id = ids[i]
+	}
+}
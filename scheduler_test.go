@@ -0,0 +1,67 @@
+package goldengine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntityTimersWaitForScene(t *testing.T) {
+	s, err := SceneFromSceneDef(&SceneDef{Name: "timers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.scheduler.update(time.Second)
+	e := NewEntity()
+	fired, ticks, steps := 0, 0, 0
+	e.After(10*time.Millisecond, func() { fired++ })
+	e.Every(10*time.Millisecond, func() { ticks++ })
+	e.Sequence(Do(func() { steps++ }), Wait(10*time.Millisecond), Do(func() { steps++ }))
+	cancelled := e.After(time.Millisecond, func() { t.Error("cancelled timer ran") })
+	cancelled.Cancel()
+
+	s.scheduler.update(time.Second)
+	if fired != 0 || ticks != 0 || steps != 0 {
+		t.Fatalf("timers of an entity outside the scene ran: %d %d %d", fired, ticks, steps)
+	}
+	s.AddEntity(e)
+	s.scheduler.update(10 * time.Millisecond)
+	if fired != 1 || ticks != 1 || steps != 1 {
+		t.Fatalf("after 10ms got after %d, every %d, sequence %d, want 1 1 1", fired, ticks, steps)
+	}
+	s.scheduler.update(10 * time.Millisecond)
+	if fired != 1 || ticks != 2 || steps != 2 {
+		t.Fatalf("after 20ms got after %d, every %d, sequence %d, want 1 2 2", fired, ticks, steps)
+	}
+}
+
+func TestEmptySequenceFinishes(t *testing.T) {
+	s := NewScheduler()
+	timer := s.Sequence()
+	s.update(time.Millisecond)
+	if !timer.Done() {
+		t.Fatal("sequence without steps didn't finish")
+	}
+}
+
+func TestRemovedEntityCancelsTimers(t *testing.T) {
+	s, err := SceneFromSceneDef(&SceneDef{Name: "timers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed, destroyed := NewEntity(), NewEntity()
+	s.AddEntity(removed)
+	s.AddEntity(destroyed)
+	removed.After(time.Millisecond, func() { t.Error("timer of a removed entity ran") })
+	destroyed.Every(time.Millisecond, func() { t.Error("timer of a destroyed entity ran") })
+	waiting := NewEntity()
+	timer := waiting.After(time.Millisecond, func() {})
+	waiting.Destroy()
+
+	s.RemoveEntity(removed)
+	destroyed.Destroy()
+	s.flushDestroyed()
+	s.scheduler.update(10 * time.Millisecond)
+	if !timer.Cancelled() {
+		t.Error("timer of an entity destroyed before joining a scene wasn't cancelled")
+	}
+}
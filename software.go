@@ -0,0 +1,353 @@
+package goldengine
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"sync"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//SoftwareBackend : WindowBackend that rasterizes shapes and sprites into an
+//image.RGBA in pure Go. Events and draw calls work like a HeadlessBackend.
+//Text and other drawers aren't rasterized, Skipped counts them. Used for
+//golden image tests
+type SoftwareBackend struct {
+	*HeadlessBackend
+	canvas *image.RGBA
+	frame  *image.RGBA
+	//Drawers left out of the canvas and of the last displayed frame
+	skipped      int
+	frameSkipped int
+	mu           sync.Mutex
+}
+
+//rasterShape : Shapes the SoftwareBackend can rasterize
+type rasterShape interface {
+	Shape
+	sf.Transformer
+	GetPointCount() uint
+	GetPoint(index uint) sf.Vector2f
+}
+
+//NewSoftwareBackend : Creates an open SoftwareBackend of the given size in pixels
+func NewSoftwareBackend(width, height uint) *SoftwareBackend {
+	headless := NewHeadlessBackend(width, height)
+	size := headless.GetSize()
+	bounds := image.Rect(0, 0, int(size.X), int(size.Y))
+	return &SoftwareBackend{
+		HeadlessBackend: headless,
+		canvas:          image.NewRGBA(bounds),
+		frame:           image.NewRGBA(bounds),
+	}
+}
+
+//Clear : Fills the canvas with color
+func (b *SoftwareBackend) Clear(color sf.Color) {
+	b.HeadlessBackend.Clear(color)
+	size := b.GetSize()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bounds := image.Rect(0, 0, int(size.X), int(size.Y))
+	if b.canvas.Bounds() != bounds {
+		b.canvas = image.NewRGBA(bounds)
+	}
+	b.skipped = 0
+	r, g, bl, a := premultiply(color)
+	pix := b.canvas.Pix
+	for i := 0; i < len(pix); i += 4 {
+		pix[i], pix[i+1], pix[i+2], pix[i+3] = r, g, bl, a
+	}
+}
+
+//Draw : Records the draw call and rasterizes it onto the canvas
+func (b *SoftwareBackend) Draw(drawer sf.Drawer, renderStates sf.RenderStates) {
+	b.HeadlessBackend.Draw(drawer, renderStates)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	parent := MatrixFromSFML(renderStates.Transform)
+	switch d := drawer.(type) {
+	case rasterShape:
+		b.drawShape(d, parent.Multiply(MatrixFromTransformer(d)))
+	case *sf.Sprite:
+		b.drawSprite(d, parent.Multiply(MatrixFromTransformer(d)))
+	default:
+		b.skipped++
+	}
+}
+
+//Display : Finishes the frame. Image returns it from now on
+func (b *SoftwareBackend) Display() {
+	b.HeadlessBackend.Display()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	frame := image.NewRGBA(b.canvas.Bounds())
+	copy(frame.Pix, b.canvas.Pix)
+	b.frame = frame
+	b.frameSkipped = b.skipped
+}
+
+//Skipped : Draw calls of the last displayed frame that weren't rasterized
+//because they aren't shapes or sprites, like Text. Its image doesn't show them
+func (b *SoftwareBackend) Skipped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.frameSkipped
+}
+
+//Image : Copy of the last displayed frame
+func (b *SoftwareBackend) Image() *image.RGBA {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	frame := image.NewRGBA(b.frame.Bounds())
+	copy(frame.Pix, b.frame.Pix)
+	return frame
+}
+
+//SavePNG : Writes the last displayed frame to a png file
+func (b *SoftwareBackend) SavePNG(path string) error {
+	return SavePNG(b.Image(), path)
+}
+
+func (b *SoftwareBackend) drawShape(shape rasterShape, m Matrix) {
+	count := int(shape.GetPointCount())
+	if count < 3 {
+		return
+	}
+	points := make([]sf.Vector2f, count)
+	for i := range points {
+		points[i] = shape.GetPoint(uint(i))
+	}
+	fill := transformPoints(m, points)
+	b.fillPolygon(fill, nil, shape.GetFillColor())
+	if thickness := shape.GetOutlineThickness(); thickness != 0 {
+		outline := transformPoints(m, outlinePoints(points, thickness))
+		b.fillPolygon(outline, fill, shape.GetOutlineColor())
+	}
+}
+
+func (b *SoftwareBackend) drawSprite(sprite *sf.Sprite, m Matrix) {
+	texture := sprite.GetTexture()
+	if texture == nil {
+		return
+	}
+	rect := sprite.GetTextureRect()
+	if rect.Width <= 0 || rect.Height <= 0 {
+		return
+	}
+	width, height := float32(rect.Width), float32(rect.Height)
+	corners := transformPoints(m, []sf.Vector2f{{X: 0, Y: 0}, {X: width, Y: 0}, {X: width, Y: height}, {X: 0, Y: height}})
+	texels := texture.CopyToImage()
+	tint := sprite.GetColor()
+	inverse := m.Inverse()
+	minX, minY, maxX, maxY := b.pixelBounds(corners)
+	for y := minY; y < maxY; y++ {
+		for x := minX; x < maxX; x++ {
+			local := inverse.TransformPoint(sf.Vector2f{X: float32(x) + 0.5, Y: float32(y) + 0.5})
+			if local.X < 0 || local.Y < 0 || local.X >= width || local.Y >= height {
+				continue
+			}
+			c := texels.GetPixel(uint(rect.Left+int(local.X)), uint(rect.Top+int(local.Y)))
+			b.blend(x, y, sf.Color{
+				R: uint8(uint16(c.R) * uint16(tint.R) / 255),
+				G: uint8(uint16(c.G) * uint16(tint.G) / 255),
+				B: uint8(uint16(c.B) * uint16(tint.B) / 255),
+				A: uint8(uint16(c.A) * uint16(tint.A) / 255),
+			})
+		}
+	}
+}
+
+//fillPolygon : Paints every pixel whose center is inside polygon but not inside hole
+func (b *SoftwareBackend) fillPolygon(polygon, hole []sf.Vector2f, color sf.Color) {
+	if color.A == 0 {
+		return
+	}
+	minX, minY, maxX, maxY := b.pixelBounds(polygon)
+	for y := minY; y < maxY; y++ {
+		for x := minX; x < maxX; x++ {
+			center := sf.Vector2f{X: float32(x) + 0.5, Y: float32(y) + 0.5}
+			if !insidePolygon(polygon, center) {
+				continue
+			}
+			if hole != nil && insidePolygon(hole, center) {
+				continue
+			}
+			b.blend(x, y, color)
+		}
+	}
+}
+
+//pixelBounds : Pixels covered by the bounding box of points, clamped to the canvas
+func (b *SoftwareBackend) pixelBounds(points []sf.Vector2f) (minX, minY, maxX, maxY int) {
+	left, top := float32(math.Inf(1)), float32(math.Inf(1))
+	right, bottom := float32(math.Inf(-1)), float32(math.Inf(-1))
+	for _, p := range points {
+		left = float32(math.Min(float64(left), float64(p.X)))
+		top = float32(math.Min(float64(top), float64(p.Y)))
+		right = float32(math.Max(float64(right), float64(p.X)))
+		bottom = float32(math.Max(float64(bottom), float64(p.Y)))
+	}
+	bounds := b.canvas.Bounds()
+	minX = clampInt(int(math.Floor(float64(left))), bounds.Min.X, bounds.Max.X)
+	minY = clampInt(int(math.Floor(float64(top))), bounds.Min.Y, bounds.Max.Y)
+	maxX = clampInt(int(math.Ceil(float64(right))), bounds.Min.X, bounds.Max.X)
+	maxY = clampInt(int(math.Ceil(float64(bottom))), bounds.Min.Y, bounds.Max.Y)
+	return
+}
+
+//blend : Draws color over the pixel at x, y
+func (b *SoftwareBackend) blend(x, y int, color sf.Color) {
+	i := b.canvas.PixOffset(x, y)
+	pix := b.canvas.Pix[i : i+4 : i+4]
+	r, g, bl, a := premultiply(color)
+	inverse := 255 - uint16(a)
+	pix[0] = r + uint8(uint16(pix[0])*inverse/255)
+	pix[1] = g + uint8(uint16(pix[1])*inverse/255)
+	pix[2] = bl + uint8(uint16(pix[2])*inverse/255)
+	pix[3] = a + uint8(uint16(pix[3])*inverse/255)
+}
+
+func premultiply(c sf.Color) (r, g, b, a uint8) {
+	alpha := uint16(c.A)
+	return uint8(uint16(c.R) * alpha / 255), uint8(uint16(c.G) * alpha / 255), uint8(uint16(c.B) * alpha / 255), c.A
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+func transformPoints(m Matrix, points []sf.Vector2f) []sf.Vector2f {
+	transformed := make([]sf.Vector2f, len(points))
+	for i, p := range points {
+		transformed[i] = m.TransformPoint(p)
+	}
+	return transformed
+}
+
+//insidePolygon : Even-odd rule
+func insidePolygon(polygon []sf.Vector2f, p sf.Vector2f) bool {
+	inside := false
+	j := len(polygon) - 1
+	for i := range polygon {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+		j = i
+	}
+	return inside
+}
+
+//outlinePoints : Moves every point along its normal by thickness the same way sfml builds outlines
+func outlinePoints(points []sf.Vector2f, thickness float32) []sf.Vector2f {
+	var center sf.Vector2f
+	for _, p := range points {
+		center.X += p.X / float32(len(points))
+		center.Y += p.Y / float32(len(points))
+	}
+	outline := make([]sf.Vector2f, len(points))
+	for i, p1 := range points {
+		p0 := points[(i+len(points)-1)%len(points)]
+		p2 := points[(i+1)%len(points)]
+		n1 := outwardNormal(p0, p1, center)
+		n2 := outwardNormal(p1, p2, center)
+		factor := 1 + (n1.X*n2.X + n1.Y*n2.Y)
+		if factor == 0 {
+			factor = 1
+		}
+		outline[i] = sf.Vector2f{
+			X: p1.X + (n1.X+n2.X)/factor*thickness,
+			Y: p1.Y + (n1.Y+n2.Y)/factor*thickness,
+		}
+	}
+	return outline
+}
+
+func outwardNormal(p1, p2, center sf.Vector2f) sf.Vector2f {
+	normal := sf.Vector2f{X: p1.Y - p2.Y, Y: p2.X - p1.X}
+	length := float32(math.Hypot(float64(normal.X), float64(normal.Y)))
+	if length != 0 {
+		normal.X /= length
+		normal.Y /= length
+	}
+	if normal.X*(center.X-p1.X)+normal.Y*(center.Y-p1.Y) > 0 {
+		normal.X, normal.Y = -normal.X, -normal.Y
+	}
+	return normal
+}
+
+//RenderSceneToImage : Rasterizes a scene onto a new image of the given size.
+//Only shapes and sprites are drawn. Text and other drawers are left out of the
+//image, which the scene's game logs. Render to a SoftwareBackend and check
+//Skipped to fail a test on them instead
+func RenderSceneToImage(s *Scene, width, height uint, clearColor sf.Color) *image.RGBA {
+	backend := NewSoftwareBackend(width, height)
+	backend.Clear(clearColor)
+	s.Render(backend, sf.DefaultRenderStates())
+	backend.Display()
+	if skipped := backend.Skipped(); skipped > 0 && s.game != nil {
+		s.game.logger.Printf("Scene %q: %d draw calls the software backend can't rasterize were left out of the image", s.Name, skipped)
+	}
+	return backend.Image()
+}
+
+//SavePNG : Writes an image to a png file
+func SavePNG(img image.Image, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = png.Encode(file, img)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+//LoadPNG : Reads an image from a png file
+func LoadPNG(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return png.Decode(file)
+}
+
+//CompareImages : Number of pixels where any channel differs by more than tolerance.
+//Errors if the images aren't the same size
+func CompareImages(got, want image.Image, tolerance uint8) (int, error) {
+	if got.Bounds().Size() != want.Bounds().Size() {
+		return 0, fmt.Errorf("Image sizes differ: got %v want %v", got.Bounds().Size(), want.Bounds().Size())
+	}
+	size := got.Bounds().Size()
+	limit := uint32(tolerance) * 0x101
+	diff := 0
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			r1, g1, b1, a1 := got.At(got.Bounds().Min.X+x, got.Bounds().Min.Y+y).RGBA()
+			r2, g2, b2, a2 := want.At(want.Bounds().Min.X+x, want.Bounds().Min.Y+y).RGBA()
+			if absDiff(r1, r2) > limit || absDiff(g1, g2) > limit || absDiff(b1, b2) > limit || absDiff(a1, a2) > limit {
+				diff++
+			}
+		}
+	}
+	return diff, nil
+}
+
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,122 @@
+package goldengine
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+func TestInsidePolygon(t *testing.T) {
+	square := []sf.Vector2f{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	//L shape missing its top right quarter
+	ell := []sf.Vector2f{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 5, Y: 5}, {X: 10, Y: 5}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	tests := []struct {
+		name    string
+		polygon []sf.Vector2f
+		p       sf.Vector2f
+		inside  bool
+	}{
+		{"square center", square, sf.Vector2f{X: 5, Y: 5}, true},
+		{"square corner pixel", square, sf.Vector2f{X: 0.5, Y: 9.5}, true},
+		{"left of square", square, sf.Vector2f{X: -0.5, Y: 5}, false},
+		{"below square", square, sf.Vector2f{X: 5, Y: 10.5}, false},
+		{"ell arm", ell, sf.Vector2f{X: 2.5, Y: 2.5}, true},
+		{"ell foot", ell, sf.Vector2f{X: 7.5, Y: 7.5}, true},
+		{"ell notch", ell, sf.Vector2f{X: 7.5, Y: 2.5}, false},
+	}
+	for _, test := range tests {
+		if inside := insidePolygon(test.polygon, test.p); inside != test.inside {
+			t.Errorf("%s: insidePolygon(%v) = %v, want %v", test.name, test.p, inside, test.inside)
+		}
+	}
+}
+
+func TestOutlinePoints(t *testing.T) {
+	square := []sf.Vector2f{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	want := []sf.Vector2f{{X: -2, Y: -2}, {X: 12, Y: -2}, {X: 12, Y: 12}, {X: -2, Y: 12}}
+	outline := outlinePoints(square, 2)
+	for i := range want {
+		if outline[i] != want[i] {
+			t.Errorf("outline point %d is %v, want %v", i, outline[i], want[i])
+		}
+	}
+	inner := outlinePoints(square, -2)
+	if inner[0] != (sf.Vector2f{X: 2, Y: 2}) || inner[2] != (sf.Vector2f{X: 8, Y: 8}) {
+		t.Errorf("negative thickness outline is %v, want it inside the square", inner)
+	}
+}
+
+func TestBlendPartialAlpha(t *testing.T) {
+	b := NewSoftwareBackend(2, 1)
+	b.Clear(sf.ColorTransparent())
+	pix := b.canvas.Pix
+	pix[0], pix[1], pix[2], pix[3] = 255, 255, 255, 255
+	red := sf.Color{R: 255, A: 128}
+	b.blend(0, 0, red)
+	b.blend(1, 0, red)
+	if got, want := pix[0:4], []byte{255, 127, 127, 255}; string(got) != string(want) {
+		t.Errorf("half red over white is %v, want %v", got, want)
+	}
+	if got, want := pix[4:8], []byte{128, 0, 0, 128}; string(got) != string(want) {
+		t.Errorf("half red over transparent is %v, want premultiplied %v", got, want)
+	}
+}
+
+func TestCompareImages(t *testing.T) {
+	got := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	want := image.NewRGBA(image.Rect(10, 10, 14, 14))
+	got.Set(1, 1, color.RGBA{R: 10, A: 255})
+	want.Set(11, 11, color.RGBA{R: 14, A: 255})
+	got.Set(2, 2, color.RGBA{G: 200, A: 255})
+	for _, test := range []struct {
+		tolerance uint8
+		diff      int
+	}{{0, 2}, {3, 2}, {4, 1}, {255, 0}} {
+		diff, err := CompareImages(got, want, test.tolerance)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff != test.diff {
+			t.Errorf("tolerance %d: %d pixels differ, want %d", test.tolerance, diff, test.diff)
+		}
+	}
+	if _, err := CompareImages(got, image.NewRGBA(image.Rect(0, 0, 4, 5)), 255); err == nil {
+		t.Error("compared images of different sizes")
+	}
+}
+
+//testDrawer : Drawer the software backend can't rasterize
+type testDrawer struct{}
+
+func (testDrawer) Draw(target sf.RenderTarget, renderStates sf.RenderStates) {}
+
+func TestSoftwareBackendCountsSkippedDrawers(t *testing.T) {
+	b := NewSoftwareBackend(4, 4)
+	shape, err := sf.NewRectangleShape()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shape.SetSize(sf.Vector2f{X: 2, Y: 2})
+	shape.SetFillColor(sf.ColorRed())
+	b.Clear(sf.ColorBlack())
+	b.Draw(shape, sf.DefaultRenderStates())
+	b.Draw(testDrawer{}, sf.DefaultRenderStates())
+	b.Draw(testDrawer{}, sf.DefaultRenderStates())
+	if b.Skipped() != 0 {
+		t.Errorf("skipped %d drawers before the frame was displayed", b.Skipped())
+	}
+	b.Display()
+	if b.Skipped() != 2 {
+		t.Errorf("skipped %d drawers, want 2", b.Skipped())
+	}
+	if r, _, _, _ := b.Image().At(0, 0).RGBA(); r != 0xffff {
+		t.Error("the shape drawn with the skipped drawers is missing")
+	}
+	b.Clear(sf.ColorBlack())
+	b.Display()
+	if b.Skipped() != 0 {
+		t.Errorf("empty frame skipped %d drawers", b.Skipped())
+	}
+}
@@ -0,0 +1,410 @@
+package goldengine
+
+import (
+	"math"
+	"sort"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//DefaultSpatialCellSize : Width and height in pixels of a cell of a scene's spatial index
+const DefaultSpatialCellSize = 128
+
+//localBounder : Transformers that know the rectangle they cover before being transformed
+type localBounder interface {
+	GetLocalBounds() sf.FloatRect
+}
+
+//WorldBounds : Rectangle the entity covers in the scene. False when its
+//Transformer has no bounds, the rectangle is then a point at WorldPosition
+func (e *Entity) WorldBounds() (sf.FloatRect, bool) {
+	bounder, ok := e.Transfrom.(localBounder)
+	if !ok {
+		p := e.WorldPosition()
+		return sf.FloatRect{Left: p.X, Top: p.Y}, false
+	}
+	local := bounder.GetLocalBounds()
+	m := e.WorldMatrix()
+	return boundingRect([]sf.Vector2f{
+		m.TransformPoint(sf.Vector2f{X: local.Left, Y: local.Top}),
+		m.TransformPoint(sf.Vector2f{X: local.Left + local.Width, Y: local.Top}),
+		m.TransformPoint(sf.Vector2f{X: local.Left, Y: local.Top + local.Height}),
+		m.TransformPoint(sf.Vector2f{X: local.Left + local.Width, Y: local.Top + local.Height}),
+	}), true
+}
+
+//boundingRect : Smallest rectangle containing every point
+func boundingRect(points []sf.Vector2f) sf.FloatRect {
+	minX, minY := points[0].X, points[0].Y
+	maxX, maxY := minX, minY
+	for _, p := range points[1:] {
+		minX = float32(math.Min(float64(minX), float64(p.X)))
+		minY = float32(math.Min(float64(minY), float64(p.Y)))
+		maxX = float32(math.Max(float64(maxX), float64(p.X)))
+		maxY = float32(math.Max(float64(maxY), float64(p.Y)))
+	}
+	return sf.FloatRect{Left: minX, Top: minY, Width: maxX - minX, Height: maxY - minY}
+}
+
+//rectsIntersect : Rectangles overlap or touch
+func rectsIntersect(a, b sf.FloatRect) bool {
+	return a.Left <= b.Left+b.Width && b.Left <= a.Left+a.Width &&
+		a.Top <= b.Top+b.Height && b.Top <= a.Top+a.Height
+}
+
+//rectDistance : Distance from a point to the closest point of a rectangle
+func rectDistance(r sf.FloatRect, p sf.Vector2f) float32 {
+	dx := math.Max(math.Max(float64(r.Left-p.X), 0), float64(p.X-(r.Left+r.Width)))
+	dy := math.Max(math.Max(float64(r.Top-p.Y), 0), float64(p.Y-(r.Top+r.Height)))
+	return float32(math.Hypot(dx, dy))
+}
+
+type spatialCell struct {
+	X, Y int
+}
+
+type spatialCells struct {
+	minX, minY, maxX, maxY int
+}
+
+type spatialEntry struct {
+	entity  *Entity
+	bounds  sf.FloatRect
+	bounded bool
+	cells   spatialCells
+}
+
+//spatialHash : Entities bucketed by the grid cells their bounds cover
+type spatialHash struct {
+	cellSize float32
+	cells    map[spatialCell]map[uint32]*spatialEntry
+	entries  map[uint32]*spatialEntry
+	//Cells that held an entity since the last full rebuild, used to bound Nearest
+	extent    spatialCells
+	extentSet bool
+}
+
+func newSpatialHash(cellSize float32) *spatialHash {
+	if cellSize <= 0 {
+		cellSize = DefaultSpatialCellSize
+	}
+	return &spatialHash{
+		cellSize: cellSize,
+		cells:    make(map[spatialCell]map[uint32]*spatialEntry),
+		entries:  make(map[uint32]*spatialEntry),
+	}
+}
+
+func (h *spatialHash) cellsOf(r sf.FloatRect) spatialCells {
+	return spatialCells{
+		minX: int(math.Floor(float64(r.Left / h.cellSize))),
+		minY: int(math.Floor(float64(r.Top / h.cellSize))),
+		maxX: int(math.Floor(float64((r.Left + r.Width) / h.cellSize))),
+		maxY: int(math.Floor(float64((r.Top + r.Height) / h.cellSize))),
+	}
+}
+
+//update : Moves an entity to the cells its current bounds cover
+func (h *spatialHash) update(e *Entity) {
+	bounds, bounded := e.WorldBounds()
+	cells := h.cellsOf(bounds)
+	entry, ok := h.entries[e.id]
+	if ok {
+		entry.bounds, entry.bounded = bounds, bounded
+		if entry.cells == cells {
+			return
+		}
+		h.unlink(entry)
+	} else {
+		entry = &spatialEntry{
+			entity:  e,
+			bounds:  bounds,
+			bounded: bounded,
+		}
+		h.entries[e.id] = entry
+	}
+	entry.cells = cells
+	h.extend(cells)
+	for x := cells.minX; x <= cells.maxX; x++ {
+		for y := cells.minY; y <= cells.maxY; y++ {
+			key := spatialCell{X: x, Y: y}
+			cell, ok := h.cells[key]
+			if !ok {
+				cell = make(map[uint32]*spatialEntry)
+				h.cells[key] = cell
+			}
+			cell[e.id] = entry
+		}
+	}
+}
+
+func (h *spatialHash) remove(id uint32) {
+	if entry, ok := h.entries[id]; ok {
+		h.unlink(entry)
+		delete(h.entries, id)
+	}
+}
+
+func (h *spatialHash) unlink(entry *spatialEntry) {
+	for x := entry.cells.minX; x <= entry.cells.maxX; x++ {
+		for y := entry.cells.minY; y <= entry.cells.maxY; y++ {
+			key := spatialCell{X: x, Y: y}
+			delete(h.cells[key], entry.entity.id)
+			if len(h.cells[key]) == 0 {
+				delete(h.cells, key)
+			}
+		}
+	}
+}
+
+//extend : Grows the extent to cover cells. It only shrinks on a full rebuild,
+//a larger extent makes Nearest look further but never miss an entity
+func (h *spatialHash) extend(cells spatialCells) {
+	if !h.extentSet {
+		h.extent, h.extentSet = cells, true
+		return
+	}
+	h.extent.minX = minInt(h.extent.minX, cells.minX)
+	h.extent.minY = minInt(h.extent.minY, cells.minY)
+	h.extent.maxX = maxInt(h.extent.maxX, cells.maxX)
+	h.extent.maxY = maxInt(h.extent.maxY, cells.maxY)
+}
+
+//visit : Calls fn once for every entry in the cells, stops when fn returns false
+func (h *spatialHash) visit(cells spatialCells, seen map[uint32]bool, fn func(*spatialEntry) bool) bool {
+	for x := cells.minX; x <= cells.maxX; x++ {
+		for y := cells.minY; y <= cells.maxY; y++ {
+			for id, entry := range h.cells[spatialCell{X: x, Y: y}] {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				if !fn(entry) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+//visitRing : visit for only the border cells of a square of cells
+func (h *spatialHash) visitRing(cells spatialCells, seen map[uint32]bool, fn func(*spatialEntry) bool) {
+	rows := []spatialCells{
+		{minX: cells.minX, minY: cells.minY, maxX: cells.maxX, maxY: cells.minY},
+		{minX: cells.minX, minY: cells.maxY, maxX: cells.maxX, maxY: cells.maxY},
+		{minX: cells.minX, minY: cells.minY + 1, maxX: cells.minX, maxY: cells.maxY - 1},
+		{minX: cells.maxX, minY: cells.minY + 1, maxX: cells.maxX, maxY: cells.maxY - 1},
+	}
+	for _, row := range rows {
+		if !h.visit(row, seen, fn) {
+			return
+		}
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+//spatialIndex : Index of the scene brought up to date with where entities are
+//now. Only entities that moved since the last query are put in new cells
+func (s *Scene) spatialIndex() *spatialHash {
+	if s.spatial == nil {
+		s.spatial = newSpatialHash(DefaultSpatialCellSize)
+		s.spatialDirty = true
+	}
+	s.movedLock.Lock()
+	moved := s.moved
+	s.moved = nil
+	s.movedLock.Unlock()
+	for _, e := range moved {
+		e.spatialQueued = false
+	}
+	if s.spatialDirty {
+		s.spatial = newSpatialHash(s.spatial.cellSize)
+		for _, e := range s.entityMap {
+			s.spatial.update(e)
+		}
+		s.spatialDirty = false
+		return s.spatial
+	}
+	for _, e := range moved {
+		s.updateSpatialTree(e)
+	}
+	return s.spatial
+}
+
+//updateSpatialTree : Puts an entity of the scene and its children in the cells they cover now
+func (s *Scene) updateSpatialTree(e *Entity) {
+	if s.entityMap[e.id] != e {
+		return
+	}
+	s.spatial.update(e)
+	for _, child := range e.children {
+		s.updateSpatialTree(child)
+	}
+}
+
+//spatialMoved : Queues an entity whose bounds may have changed for the next
+//spatial query. Safe to call from parallel updates
+func (s *Scene) spatialMoved(e *Entity) {
+	if s.spatial == nil {
+		return
+	}
+	s.movedLock.Lock()
+	if !e.spatialQueued {
+		e.spatialQueued = true
+		s.moved = append(s.moved, e)
+	}
+	s.movedLock.Unlock()
+}
+
+//RefreshSpatialIndex : Makes the next spatial query put every entity in new
+//cells. Moving entities with SetPosition, Move, SetRotation and SetScale or
+//calling MarkTransformDirty already updates them, call it after changing many
+//Transformers directly
+func (s *Scene) RefreshSpatialIndex() {
+	s.spatialDirty = true
+}
+
+//SetSpatialCellSize : Changes the cell size of the spatial index. Cells about
+//the size of the common entity keep queries fast
+func (s *Scene) SetSpatialCellSize(size float32) {
+	s.spatial = newSpatialHash(size)
+	s.spatialDirty = true
+}
+
+//QueryRect : Entities whose bounds overlap a rectangle, in the order they were created
+func (s *Scene) QueryRect(rect sf.FloatRect) []*Entity {
+	h := s.spatialIndex()
+	var list []*Entity
+	h.visit(h.cellsOf(rect), make(map[uint32]bool), func(entry *spatialEntry) bool {
+		if !entry.entity.destroyed && entry.entity.ActiveInHierarchy() && rectsIntersect(entry.bounds, rect) {
+			list = append(list, entry.entity)
+		}
+		return true
+	})
+	sortEntities(list)
+	return list
+}
+
+//QueryRadius : Entities whose bounds are within radius of center, in the order they were created
+func (s *Scene) QueryRadius(center sf.Vector2f, radius float32) []*Entity {
+	h := s.spatialIndex()
+	rect := sf.FloatRect{Left: center.X - radius, Top: center.Y - radius, Width: 2 * radius, Height: 2 * radius}
+	var list []*Entity
+	h.visit(h.cellsOf(rect), make(map[uint32]bool), func(entry *spatialEntry) bool {
+		if !entry.entity.destroyed && entry.entity.ActiveInHierarchy() && rectDistance(entry.bounds, center) <= radius {
+			list = append(list, entry.entity)
+		}
+		return true
+	})
+	sortEntities(list)
+	return list
+}
+
+//Nearest : Entity whose bounds are closest to point and pass filter. A nil
+//filter passes everything and a maxDistance of 0 or less means no limit
+func (s *Scene) Nearest(point sf.Vector2f, maxDistance float32, filter func(*Entity) bool) (*Entity, bool) {
+	h := s.spatialIndex()
+	if len(h.entries) == 0 {
+		return nil, false
+	}
+	var best *Entity
+	bestDistance := float32(math.Inf(1))
+	center := h.cellsOf(sf.FloatRect{Left: point.X, Top: point.Y})
+	seen := make(map[uint32]bool)
+	consider := func(entry *spatialEntry) bool {
+		if entry.entity.destroyed || !entry.entity.ActiveInHierarchy() || (filter != nil && !filter(entry.entity)) {
+			return true
+		}
+		distance := rectDistance(entry.bounds, point)
+		if distance < bestDistance || (distance == bestDistance && entry.entity.id < best.id) {
+			best, bestDistance = entry.entity, distance
+		}
+		return true
+	}
+	for ring := 0; ; ring++ {
+		cells := spatialCells{
+			minX: center.minX - ring, minY: center.minY - ring,
+			maxX: center.maxX + ring, maxY: center.maxY + ring,
+		}
+		//Far from every entity checking them all is cheaper than walking empty cells
+		if side := 2*ring + 1; side*side > 4*len(h.entries) {
+			for _, entry := range h.entries {
+				consider(entry)
+			}
+			break
+		}
+		h.visitRing(cells, seen, consider)
+		//Cells outside this ring are at least ring cells away
+		reach := float32(ring) * h.cellSize
+		if best != nil && bestDistance <= reach {
+			break
+		}
+		if maxDistance > 0 && reach > maxDistance {
+			break
+		}
+		if cells.minX <= h.extent.minX && cells.minY <= h.extent.minY &&
+			cells.maxX >= h.extent.maxX && cells.maxY >= h.extent.maxY {
+			break
+		}
+	}
+	if best == nil || (maxDistance > 0 && bestDistance > maxDistance) {
+		return nil, false
+	}
+	return best, true
+}
+
+//visibleNodes : Nodes of enabled entities that can show up on the target, in
+//tree order. Entities without bounds are always drawn. Every enabled node when
+//the target's size is unknown
+func (s *Scene) visibleNodes(target Renderer, renderStates sf.RenderStates) []*entityNode {
+	var view sf.FloatRect
+	var h *spatialHash
+	if sized, ok := target.(interface {
+		GetSize() sf.Vector2u
+	}); ok {
+		size := sized.GetSize()
+		inverse := MatrixFromSFML(renderStates.Transform).Inverse()
+		view = boundingRect([]sf.Vector2f{
+			inverse.TransformPoint(sf.Vector2f{}),
+			inverse.TransformPoint(sf.Vector2f{X: float32(size.X)}),
+			inverse.TransformPoint(sf.Vector2f{Y: float32(size.Y)}),
+			inverse.TransformPoint(sf.Vector2f{X: float32(size.X), Y: float32(size.Y)}),
+		})
+		h = s.spatialIndex()
+	}
+	order := s.nodeOrder()
+	nodes := make([]*entityNode, 0, len(order))
+	for _, node := range order {
+		if !node.entity.ActiveInHierarchy() {
+			continue
+		}
+		if h != nil {
+			if entry, ok := h.entries[node.entity.id]; ok && entry.bounded && !rectsIntersect(entry.bounds, view) {
+				continue
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+//sortEntities : Sorts entities in the order they were created
+func sortEntities(list []*Entity) {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].id < list[j].id
+	})
+}
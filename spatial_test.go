@@ -0,0 +1,53 @@
+package goldengine
+
+import (
+	"testing"
+
+	sf "github.com/manyminds/gosfml"
+)
+
+//newBoxEntity : Entity covering a 10 by 10 square from position
+func newBoxEntity(t *testing.T, position sf.Vector2f) *Entity {
+	t.Helper()
+	e := newShapeEntity(t, position)
+	e.Transfrom.(*sf.RectangleShape).SetSize(sf.Vector2f{X: 10, Y: 10})
+	return e
+}
+
+func TestSpatialIndexUpdatesMovedEntities(t *testing.T) {
+	s, err := SceneFromSceneDef(&SceneDef{Name: "spatial"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	still := newBoxEntity(t, sf.Vector2f{X: 0, Y: 0})
+	mover := newBoxEntity(t, sf.Vector2f{X: 500, Y: 0})
+	child := newBoxEntity(t, sf.Vector2f{X: 0, Y: 20})
+	mover.AddChild(child)
+	s.AddEntity(still)
+	s.AddEntity(mover)
+	near := sf.FloatRect{Width: 25, Height: 15}
+	if found := s.QueryRect(near); len(found) != 1 || found[0] != still {
+		t.Fatalf("found %v near the origin, want only the still entity", found)
+	}
+	index := s.spatialIndex()
+
+	mover.SetPosition(sf.Vector2f{X: 20, Y: 0})
+	if found := s.QueryRect(near); len(found) != 2 || found[0] != still || found[1] != mover {
+		t.Fatalf("found %v after moving, want the still and moving entities", found)
+	}
+	if found := s.QueryRect(sf.FloatRect{Left: 20, Top: 20, Width: 5, Height: 5}); len(found) != 1 || found[0] != child {
+		t.Fatalf("found %v where the child moved with its parent, want the child", found)
+	}
+	if s.spatialIndex() != index {
+		t.Fatal("moving an entity rebuilt the whole index")
+	}
+
+	still.Transfrom.SetPosition(sf.Vector2f{X: 1000, Y: 1000})
+	if found := s.QueryRect(near); len(found) != 2 {
+		t.Fatalf("entities that didn't report a move were looked at again, found %v", found)
+	}
+	still.MarkTransformDirty()
+	if found := s.QueryRect(near); len(found) != 1 || found[0] != mover {
+		t.Fatalf("found %v after MarkTransformDirty, want only the moving entity", found)
+	}
+}
@@ -0,0 +1,205 @@
+package goldengine
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+//SystemStage : When the Update of a System runs relative to physics and drawing
+type SystemStage int
+
+const (
+	//StageBeforePhysics : After the scene's components and World systems,
+	//before its physics step. The default
+	StageBeforePhysics SystemStage = iota
+	//StageAfterPhysics : After the scene's physics step
+	StageAfterPhysics
+	//StageBeforeRender : Once per frame after the simulation caught up and
+	//bodies were interpolated, before the frame is drawn. Gets the frame's time
+	//instead of the fixed time step, 0 while the game is paused
+	StageBeforeRender
+)
+
+//System : Logic about a whole scene rather than one entity, like scoring,
+//spawning waves or AI directors. A System added to the Game runs for every
+//updating scene, one added to a Scene only for that scene. Each hook gets the
+//scene it runs for, whose Query, FindByTag, QueryRect and World find the
+//entities to work on.
+//PreUpdate runs at the start of the scene's fixed update before its
+//components, Update at the system's stage and PostUpdate at the end of the
+//fixed update after physics. Systems run by UpdatePriority, lowest first, when
+//they implement Prioritizer, then Game systems before Scene systems in the
+//order they were added
+type System interface {
+	PreUpdate(s *Scene, dur time.Duration)
+	Update(s *Scene, dur time.Duration)
+	PostUpdate(s *Scene, dur time.Duration)
+}
+
+//Stager : Optional System method. Stage the system's Update runs at.
+//StageBeforePhysics when not implemented
+type Stager interface {
+	Stage() SystemStage
+}
+
+//BaseSystem : Embed it to only write the hooks a System needs
+type BaseSystem struct{}
+
+//PreUpdate : Called at the start of every fixed update
+func (b BaseSystem) PreUpdate(s *Scene, dur time.Duration) {}
+
+//Update : Called every fixed update at the system's stage
+func (b BaseSystem) Update(s *Scene, dur time.Duration) {}
+
+//PostUpdate : Called at the end of every fixed update
+func (b BaseSystem) PostUpdate(s *Scene, dur time.Duration) {}
+
+//systemList : Systems in the order they were added. Changes make a new slice
+//so systems running from the old one aren't disturbed
+type systemList struct {
+	systems []System
+	version uint64
+}
+
+func (l *systemList) add(system System) {
+	if l.index(system) >= 0 {
+		return
+	}
+	systems := make([]System, len(l.systems), len(l.systems)+1)
+	copy(systems, l.systems)
+	l.systems = append(systems, system)
+	l.version++
+}
+
+func (l *systemList) remove(system System) bool {
+	i := l.index(system)
+	if i < 0 {
+		return false
+	}
+	systems := make([]System, 0, len(l.systems)-1)
+	systems = append(systems, l.systems[:i]...)
+	l.systems = append(systems, l.systems[i+1:]...)
+	l.version++
+	return true
+}
+
+//index : Position of a system, -1 if it isn't there. Systems of types that
+//can't be compared, like funcs, are never found so they can't be removed
+func (l *systemList) index(system System) int {
+	if !reflect.TypeOf(system).Comparable() {
+		return -1
+	}
+	for i, s := range l.systems {
+		if reflect.TypeOf(s) == reflect.TypeOf(system) && s == system {
+			return i
+		}
+	}
+	return -1
+}
+
+//systemOrder : Game and Scene systems of a scene in the order they run, and
+//the versions of the lists it was made from
+type systemOrder struct {
+	systems      []System
+	gameVersion  uint64
+	sceneVersion uint64
+	valid        bool
+}
+
+//AddSystem : Runs a system for every updating scene. Adding it twice does nothing
+func (g *Game) AddSystem(system System) {
+	g.runOnLoop(func() {
+		g.systems.add(system)
+	})
+}
+
+//RemoveSystem : Stops running a system added with AddSystem
+func (g *Game) RemoveSystem(system System) {
+	g.runOnLoop(func() {
+		g.systems.remove(system)
+	})
+}
+
+//AddSystem : Runs a system while the scene updates. Adding it twice does nothing
+func (s *Scene) AddSystem(system System) {
+	s.systems.add(system)
+}
+
+//RemoveSystem : Stops running a system added with AddSystem. False if it wasn't added
+func (s *Scene) RemoveSystem(system System) bool {
+	return s.systems.remove(system)
+}
+
+//orderedSystems : Systems that run for the scene, sorted again only when one was
+//added or removed
+func (s *Scene) orderedSystems() []System {
+	var game *systemList
+	if s.game != nil {
+		game = &s.game.systems
+	}
+	order := &s.systemOrder
+	if order.valid && order.sceneVersion == s.systems.version && (game == nil || order.gameVersion == game.version) {
+		return order.systems
+	}
+	var systems []System
+	if game != nil {
+		systems = append(systems, game.systems...)
+		order.gameVersion = game.version
+	}
+	systems = append(systems, s.systems.systems...)
+	sort.SliceStable(systems, func(i, j int) bool {
+		return systemPriority(systems[i]) < systemPriority(systems[j])
+	})
+	order.systems = systems
+	order.sceneVersion = s.systems.version
+	order.valid = true
+	return systems
+}
+
+func systemPriority(system System) int {
+	if p, ok := system.(Prioritizer); ok {
+		return p.UpdatePriority()
+	}
+	return 0
+}
+
+func systemStage(system System) SystemStage {
+	if s, ok := system.(Stager); ok {
+		return s.Stage()
+	}
+	return StageBeforePhysics
+}
+
+//preUpdateSystems : PreUpdate of every system of the scene
+func (s *Scene) preUpdateSystems(dur time.Duration) {
+	profiler := s.profiler()
+	for _, system := range s.orderedSystems() {
+		start := profiler.now()
+		system.PreUpdate(s, dur)
+		profiler.system(system, "PreUpdate", start)
+	}
+}
+
+//updateSystems : Update of the scene's systems at a stage
+func (s *Scene) updateSystems(stage SystemStage, dur time.Duration) {
+	profiler := s.profiler()
+	for _, system := range s.orderedSystems() {
+		if systemStage(system) != stage {
+			continue
+		}
+		start := profiler.now()
+		system.Update(s, dur)
+		profiler.system(system, "Update", start)
+	}
+}
+
+//postUpdateSystems : PostUpdate of every system of the scene
+func (s *Scene) postUpdateSystems(dur time.Duration) {
+	profiler := s.profiler()
+	for _, system := range s.orderedSystems() {
+		start := profiler.now()
+		system.PostUpdate(s, dur)
+		profiler.system(system, "PostUpdate", start)
+	}
+}
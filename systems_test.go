@@ -0,0 +1,72 @@
+package goldengine
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+//testSystem : Logs its hooks as name.Hook
+type testSystem struct {
+	name     string
+	stage    SystemStage
+	priority int
+	log      *[]string
+}
+
+func (s *testSystem) PreUpdate(scene *Scene, dur time.Duration) {
+	*s.log = append(*s.log, s.name+".PreUpdate")
+}
+
+func (s *testSystem) Update(scene *Scene, dur time.Duration) {
+	*s.log = append(*s.log, fmt.Sprintf("%s.Update %v", s.name, dur))
+}
+
+func (s *testSystem) PostUpdate(scene *Scene, dur time.Duration) {
+	*s.log = append(*s.log, s.name+".PostUpdate")
+}
+
+func (s *testSystem) Stage() SystemStage {
+	return s.stage
+}
+
+func (s *testSystem) UpdatePriority() int {
+	return s.priority
+}
+
+func TestSystemStages(t *testing.T) {
+	game, scene := newEmptySceneGame(t)
+	var log []string
+	e, life := newLifeEntity("player")
+	life.update = func() {
+		log = append(log, "component")
+	}
+	scene.AddEntity(e)
+	scene.AddSystem(&testSystem{name: "after", stage: StageAfterPhysics, priority: -1, log: &log})
+	scene.AddSystem(&testSystem{name: "render", stage: StageBeforeRender, log: &log})
+	scene.AddSystem(&testSystem{name: "scene", log: &log})
+	game.AddSystem(&testSystem{name: "game", log: &log})
+	game.Start()
+	defer game.Stop()
+
+	game.Tick(25 * time.Millisecond)
+	step := []string{
+		"after.PreUpdate", "game.PreUpdate", "render.PreUpdate", "scene.PreUpdate",
+		"component",
+		"game.Update 10ms", "scene.Update 10ms",
+		"after.Update 10ms",
+		"after.PostUpdate", "game.PostUpdate", "render.PostUpdate", "scene.PostUpdate",
+	}
+	want := append(append(append([]string(nil), step...), step...), "render.Update 25ms")
+	if !reflect.DeepEqual(log, want) {
+		t.Fatalf("two steps and a frame ran\n%v\nwant\n%v", log, want)
+	}
+
+	log = nil
+	game.Pause()
+	game.Tick(25 * time.Millisecond)
+	if !reflect.DeepEqual(log, []string{"render.Update 0s"}) {
+		t.Errorf("paused frame ran %v, want only render.Update 0s", log)
+	}
+}
@@ -0,0 +1,129 @@
+package goldengine
+
+//AddTag : Tags the entity. Tagging it twice does nothing
+func (e *Entity) AddTag(tag string) {
+	if tag == "" || e.HasTag(tag) {
+		return
+	}
+	e.tags = append(e.tags, tag)
+	if e.scene != nil {
+		e.scene.indexTag(e, tag)
+	}
+}
+
+//HasTag : Entity has the tag
+func (e *Entity) HasTag(tag string) bool {
+	return containsString(e.tags, tag)
+}
+
+//RemoveTag : Takes a tag off the entity. False if it didn't have it
+func (e *Entity) RemoveTag(tag string) bool {
+	for i, t := range e.tags {
+		if t == tag {
+			e.tags = append(e.tags[:i], e.tags[i+1:]...)
+			if e.scene != nil {
+				e.scene.unindexTag(e, tag)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+//Tags : Tags of the entity in the order they were added
+func (e *Entity) Tags() []string {
+	return append([]string(nil), e.tags...)
+}
+
+//addTags : Adds every tag of the list
+func (e *Entity) addTags(tags []string) {
+	for _, tag := range tags {
+		e.AddTag(tag)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+//tagIndex : Entities of the scene by tag. Built from the entities the first
+//time it's needed, then kept up to date as entities and tags change. Callers
+//hold tagLock, since components updating in parallel tag entities
+func (s *Scene) tagIndex() map[string]map[uint32]*Entity {
+	if s.tags == nil {
+		s.tags = make(map[string]map[uint32]*Entity)
+		for _, e := range s.entityMap {
+			for _, tag := range e.tags {
+				s.addToTagIndex(e, tag)
+			}
+		}
+	}
+	return s.tags
+}
+
+func (s *Scene) indexTag(e *Entity, tag string) {
+	s.tagLock.Lock()
+	defer s.tagLock.Unlock()
+	s.addToTagIndex(e, tag)
+}
+
+func (s *Scene) addToTagIndex(e *Entity, tag string) {
+	if s.tags == nil {
+		return
+	}
+	tagged, ok := s.tags[tag]
+	if !ok {
+		tagged = make(map[uint32]*Entity)
+		s.tags[tag] = tagged
+	}
+	tagged[e.id] = e
+}
+
+func (s *Scene) unindexTag(e *Entity, tag string) {
+	s.tagLock.Lock()
+	defer s.tagLock.Unlock()
+	if s.tags == nil {
+		return
+	}
+	tagged := s.tags[tag]
+	if tagged[e.id] != e {
+		return
+	}
+	delete(tagged, e.id)
+	if len(tagged) == 0 {
+		delete(s.tags, tag)
+	}
+}
+
+//FindByTag : Entities of the scene with the tag in the order they were created
+func (s *Scene) FindByTag(tag string) []*Entity {
+	s.tagLock.Lock()
+	tagged := s.tagIndex()[tag]
+	list := make([]*Entity, 0, len(tagged))
+	for _, e := range tagged {
+		if !e.destroyed {
+			list = append(list, e)
+		}
+	}
+	s.tagLock.Unlock()
+	sortEntities(list)
+	return list
+}
+
+//CountTag : Number of entities of the scene with the tag
+func (s *Scene) CountTag(tag string) int {
+	s.tagLock.Lock()
+	defer s.tagLock.Unlock()
+	count := 0
+	for _, e := range s.tagIndex()[tag] {
+		if !e.destroyed {
+			count++
+		}
+	}
+	return count
+}
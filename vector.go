@@ -73,3 +73,19 @@ func ChipmunkFloatToVector(x, y vect.Float) Vector {
 		Y: float32(y) / scale,
 	}
 }
+
+//Vector2fToChipmunk : Converts an sfml vector to a chipmunk vector. Both are in pixels
+func Vector2fToChipmunk(vec sf.Vector2f) vect.Vect {
+	return vect.Vect{
+		X: vect.Float(vec.X),
+		Y: vect.Float(vec.Y),
+	}
+}
+
+//ChipmunkToVector2f : Converts a chipmunk vector to an sfml vector. Both are in pixels
+func ChipmunkToVector2f(vec vect.Vect) sf.Vector2f {
+	return sf.Vector2f{
+		X: float32(vec.X),
+		Y: float32(vec.Y),
+	}
+}
@@ -2,6 +2,7 @@ package goldengine
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	sf "github.com/manyminds/gosfml"
@@ -13,15 +14,27 @@ type WindowConfig struct {
 	Height     uint
 	ClearColor sf.Color
 	Title      string
+	//FrameRate : Frames drawn per second. Independent of the update rate
+	FrameRate uint
+	//Backend : Where the window gets events from and draws to.
+	//Defaults to an SFML RenderWindow
+	Backend WindowBackend
 }
 
-//Window : Wrapper around a SFML RenderWindow Handles input and drawing scenes
+//Window : Wrapper around a WindowBackend Handles input and drawing scenes
 type Window struct {
 	Ticker          *time.Ticker
 	ClearColor      sf.Color
-	renderWindow    *sf.RenderWindow
-	scene           *Scene
+	backend         WindowBackend
+	scenes          sceneStack
+	transition      *runningTransition
 	inputCollection *InputCollection
+	profiler        *Profiler
+	//frameLock : Held while the scene is updated or drawn
+	frameLock sync.Mutex
+	done      chan struct{}
+	closeOnce sync.Once
+	stopped   chan struct{}
 
 	BasicMailBox
 }
@@ -33,6 +46,8 @@ const (
 	DefaultGameHeight = 600
 	//DefaultTitle : Title of Game Window
 	DefaultTitle = "Gold Engine"
+	//DefaultFrameRate : Frames drawn per second
+	DefaultFrameRate = 60
 )
 
 func newWindow(config WindowConfig) *Window {
@@ -50,12 +65,21 @@ func newWindow(config WindowConfig) *Window {
 	if gameTitle == "" {
 		gameTitle = DefaultTitle
 	}
-	NewScreenWidth(gameWidth)
+	frameRate := config.FrameRate
+	if frameRate <= 0 {
+		frameRate = DefaultFrameRate
+	}
+	backend := config.Backend
+	if backend == nil {
+		backend = sf.NewRenderWindow(sf.VideoMode{Width: gameWidth, Height: gameHeight, BitsPerPixel: 32}, gameTitle, sf.StyleDefault, sf.DefaultContextSettings())
+	}
+	NewScreenWidth(backend.GetSize().X)
 	w := &Window{
-		renderWindow:    sf.NewRenderWindow(sf.VideoMode{Width: gameWidth, Height: gameHeight, BitsPerPixel: 32}, gameTitle, sf.StyleDefault, sf.DefaultContextSettings()),
-		Ticker:          time.NewTicker(time.Second / 60),
+		backend:         backend,
+		Ticker:          time.NewTicker(time.Second / time.Duration(frameRate)),
 		ClearColor:      config.ClearColor,
 		inputCollection: GenInputCollection(),
+		done:            make(chan struct{}),
 	}
 	return w
 }
@@ -65,9 +89,58 @@ func (w *Window) GetInputCollection() *InputCollection {
 	return w.inputCollection
 }
 
-//ChangeScene : Changes current scene
+//GetBackend : Returns what the window draws to
+func (w *Window) GetBackend() WindowBackend {
+	return w.backend
+}
+
+//IsOpen : Whether the window is still open
+func (w *Window) IsOpen() bool {
+	return w.backend.IsOpen()
+}
+
+//ChangeScene : Replaces every scene with s
 func (w *Window) ChangeScene(s *Scene) {
-	w.scene = s
+	w.scenes = sceneStack{{Scene: s}}
+}
+
+//PushScene : Puts a scene on top of the others
+func (w *Window) PushScene(layer *SceneLayer) {
+	w.scenes = append(w.scenes, layer)
+}
+
+//PopScene : Removes the top scene
+func (w *Window) PopScene() {
+	if len(w.scenes) > 0 {
+		w.scenes = w.scenes[:len(w.scenes)-1]
+	}
+}
+
+//GetScene : Scene on top of the scene stack
+func (w *Window) GetScene() *Scene {
+	return w.scenes.top()
+}
+
+//startTransition : Draws t from the scenes in from to the current scenes
+func (w *Window) startTransition(t Transition, from sceneStack) {
+	w.transition = nil
+	if t != nil && t.Duration() > 0 {
+		w.transition = &runningTransition{
+			transition: t,
+			from:       from,
+		}
+	}
+}
+
+//advanceTransition : Moves the running transition along by real elapsed time
+func (w *Window) advanceTransition(elapsed time.Duration) {
+	if w.transition == nil {
+		return
+	}
+	w.transition.elapsed += elapsed
+	if w.transition.elapsed >= w.transition.transition.Duration() {
+		w.transition = nil
+	}
 }
 
 //Init : Subscribes to messages and other Stuff
@@ -83,38 +156,97 @@ func (w *Window) RecieveMessage(msg Message) {
 	switch msg.Message {
 	case SceneChangedMSG:
 		w.ChangeScene(msg.Content.(*Scene))
+	case ScenePushedMSG:
+		w.PushScene(msg.Content.(*SceneLayer))
+	case ScenePoppedMSG:
+		w.PopScene()
+	case SceneReloadedMSG:
+		reload := msg.Content.(*SceneReload)
+		for _, layer := range w.scenes {
+			if layer.Scene == reload.Old {
+				layer.Scene = reload.New
+			}
+		}
+	}
+}
+
+//PollEvents : Posts every pending window event as a message
+func (w *Window) PollEvents() {
+	for event := w.backend.PollEvent(); event != nil; event = w.backend.PollEvent() {
+		switch ev := event.(type) {
+		case sf.EventKeyPressed:
+			var code = ev.Code
+			msg := Message{KeyPressedMSG, &code}
+			w.PostMessage(msg)
+		case sf.EventKeyReleased:
+			var code = ev.Code
+			msg := Message{KeyReleasedMSG, &code}
+			w.PostMessage(msg)
+		case sf.EventClosed:
+			w.backend.Close()
+		case sf.EventResized:
+			NewScreenWidth(ev.Width)
+		}
+	}
+}
+
+//Render : Draws the scene stack, or the running transition, and displays it
+func (w *Window) Render() {
+	w.frameLock.Lock()
+	start := w.profiler.now()
+	w.backend.Clear(w.ClearColor)
+	if t := w.transition; t != nil {
+		t.transition.Draw(TransitionFrame{
+			Target:   w.backend,
+			Size:     w.backend.GetSize(),
+			Progress: float32(t.elapsed) / float32(t.transition.Duration()),
+			DrawFrom: t.from.draw,
+			DrawTo:   w.scenes.draw,
+		})
+	} else {
+		w.scenes.draw(w.backend, sf.DefaultRenderStates())
 	}
+	w.profiler.end("render", "Render", start, profileRenderThread)
+	w.frameLock.Unlock()
+
+	w.backend.Display()
+}
+
+//Start : Runs the window on its own goroutine. Close joins it
+func (w *Window) Start() {
+	w.stopped = make(chan struct{})
+	go func() {
+		defer close(w.stopped)
+		w.Run()
+	}()
+}
+
+//Close : Stops Run, waits for it to return, stops the Ticker and closes the backend
+func (w *Window) Close() {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		if w.stopped != nil {
+			<-w.stopped
+		}
+		w.Ticker.Stop()
+		if w.backend.IsOpen() {
+			w.backend.Close()
+		}
+	})
 }
 
-//Run : Plays the window
+//Run : Plays the window until it's closed
 func (w *Window) Run() {
-	if w.scene == nil {
+	if len(w.scenes) == 0 {
 		panic(fmt.Errorf("No Scene"))
 	}
-	for w.renderWindow.IsOpen() {
+	for w.backend.IsOpen() {
 		select {
+		case <-w.done:
+			return
 		case <-w.Ticker.C:
-			for event := w.renderWindow.PollEvent(); event != nil; event = w.renderWindow.PollEvent() {
-				switch ev := event.(type) {
-				case sf.EventKeyPressed:
-					var code = ev.Code
-					msg := Message{KeyPressedMSG, &code}
-					w.PostMessage(msg)
-				case sf.EventKeyReleased:
-					var code = ev.Code
-					msg := Message{KeyReleasedMSG, &code}
-					w.PostMessage(msg)
-				case sf.EventClosed:
-					w.renderWindow.Close()
-				case sf.EventResized:
-					NewScreenWidth(ev.Width)
-				}
-			}
-
-			w.renderWindow.Clear(w.ClearColor)
-			w.renderWindow.Draw(w.scene, sf.DefaultRenderStates())
-
-			w.renderWindow.Display()
+			w.PollEvents()
+			w.Render()
 		}
 	}
 }